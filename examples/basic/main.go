@@ -37,24 +37,19 @@ func main() {
 	client := openfeature.NewClient("basic-example")
 
 	// Define evaluation context
-	evalCtx := openfeature.NewEvaluationContext(
-		"user-123",
-		map[string]interface{}{
-			"ipAddress": "203.0.113.42",
-			"user": map[string]interface{}{
-				"id":    "user-123",
-				"email": "user@example.com",
-				"name":  "John Doe",
-				"customAttributes": map[string]interface{}{
-					"role": "admin",
-				},
-			},
-			"customAttributes": map[string]interface{}{
-				"subscriptionLevel": "premium",
-				"region":            "us-east",
-			},
-		},
-	)
+	evalCtx, err := toggle.NewContextBuilder().
+		TargetingKey("user-123").
+		IPAddress("203.0.113.42").
+		UserID("user-123").
+		UserEmail("user@example.com").
+		UserName("John Doe").
+		UserAttribute("role", "admin").
+		Attribute("subscriptionLevel", "premium").
+		Attribute("region", "us-east").
+		Build()
+	if err != nil {
+		log.Fatalf("Failed to build evaluation context: %v", err)
+	}
 
 	// Add context with logger
 	ctx := context.Background()
@@ -77,11 +72,12 @@ func main() {
 	flagKey := "gamma"
 	defaultGamma := GammaStruct{}
 
-	result, err := client.ObjectValueDetails(ctx, flagKey, defaultGamma, evalCtx)
+	// Decode the object flag directly into GammaStruct
+	gamma, err := toggle.ObjectValue(ctx, client, flagKey, defaultGamma, evalCtx)
 	if err != nil {
 		log.Printf("Evaluation context: %+v", evalCtx)
 		log.Fatalf("Error evaluating flag: %v", err)
 	}
 
-	fmt.Printf("Feature flag '%s' is %s\n", flagKey, result)
+	fmt.Printf("Feature flag '%s' is %+v\n", flagKey, gamma)
 }
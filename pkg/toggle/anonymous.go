@@ -0,0 +1,111 @@
+package toggle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// AnonymousKeyStrategy derives the targeting key for evaluations that carry neither
+// a targeting key nor a user id. attributes are the evaluation context attributes,
+// including "application" and "environment".
+type AnonymousKeyStrategy interface {
+	AnonymousKey(attributes map[string]interface{}) (string, error)
+}
+
+// AnonymousKeyFunc adapts a function to the AnonymousKeyStrategy interface.
+type AnonymousKeyFunc func(attributes map[string]interface{}) (string, error)
+
+func (f AnonymousKeyFunc) AnonymousKey(attributes map[string]interface{}) (string, error) {
+	return f(attributes)
+}
+
+// RandomAnonymousKey generates a new random key for every evaluation. It is the
+// default strategy; prefer a deterministic one so rollouts stay stable per visitor.
+func RandomAnonymousKey() AnonymousKeyStrategy {
+	return AnonymousKeyFunc(func(attributes map[string]interface{}) (string, error) {
+		application, _ := attributes["application"].(string)
+		environment, _ := attributes["environment"].(string)
+		return generateTargetingKey(application, environment), nil
+	})
+}
+
+// HashedAnonymousKey derives the key from a SHA-256 hash of the given attributes,
+// so the same visitor always gets the same key. Nested attributes are addressed
+// with dotted paths such as "user.email". Evaluations where none of the attributes
+// are present are rejected with ErrMissingTargetKey.
+func HashedAnonymousKey(paths ...string) AnonymousKeyStrategy {
+	return AnonymousKeyFunc(func(attributes map[string]interface{}) (string, error) {
+		h := sha256.New()
+		found := false
+		for _, path := range paths {
+			value, ok := lookupPath(attributes, path)
+			if ok {
+				found = true
+			}
+			fmt.Fprintf(h, "%s=%v\x00", path, value)
+		}
+		if !found {
+			return "", fmt.Errorf("%w: none of %s are set", ErrMissingTargetKey, strings.Join(paths, ", "))
+		}
+
+		application, _ := attributes["application"].(string)
+		environment, _ := attributes["environment"].(string)
+		return application + "-" + environment + "-" + hex.EncodeToString(h.Sum(nil))[:16], nil
+	})
+}
+
+// ProcessAnonymousKey returns one random key per strategy instance, shared by every
+// anonymous evaluation in the process. It suits CLI tools and agents that act for a single user.
+func ProcessAnonymousKey() AnonymousKeyStrategy {
+	var (
+		once sync.Once
+		key  string
+	)
+	return AnonymousKeyFunc(func(attributes map[string]interface{}) (string, error) {
+		once.Do(func() {
+			application, _ := attributes["application"].(string)
+			environment, _ := attributes["environment"].(string)
+			key = generateTargetingKey(application, environment)
+		})
+		return key, nil
+	})
+}
+
+// RejectAnonymous rejects evaluations that have no targeting key or user id.
+func RejectAnonymous() AnonymousKeyStrategy {
+	return AnonymousKeyFunc(func(attributes map[string]interface{}) (string, error) {
+		return "", ErrMissingTargetKey
+	})
+}
+
+// lookupPath resolves a dotted path such as "user.email" through nested attribute maps.
+func lookupPath(attributes map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = attributes
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// resolveTargetingKey returns the targeting key for attributes that lack one:
+// the user id when present, otherwise the key from the configured anonymous strategy.
+func (p *Provider) resolveTargetingKey(attributes map[string]interface{}) (string, error) {
+	if userID, ok := getUserID(attributes); ok {
+		return userID, nil
+	}
+	strategy := p.settings().AnonymousKey
+	if strategy == nil {
+		strategy = RandomAnonymousKey()
+	}
+	return strategy.AnonymousKey(attributes)
+}
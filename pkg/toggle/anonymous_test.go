@@ -0,0 +1,127 @@
+package toggle
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashedAnonymousKey(t *testing.T) {
+	strategy := HashedAnonymousKey("ipAddress", "userAgent")
+	visitor := map[string]interface{}{
+		"application": "test-app",
+		"environment": "test-env",
+		"ipAddress":   "203.0.113.42",
+		"userAgent":   "Mozilla/5.0",
+	}
+
+	first, err := strategy.AnonymousKey(visitor)
+	assert.NoError(t, err)
+	second, err := strategy.AnonymousKey(visitor)
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.True(t, strings.HasPrefix(first, "test-app-test-env-"))
+
+	other := map[string]interface{}{
+		"application": "test-app",
+		"environment": "test-env",
+		"ipAddress":   "198.51.100.7",
+		"userAgent":   "Mozilla/5.0",
+	}
+	third, err := strategy.AnonymousKey(other)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, third)
+
+	_, err = strategy.AnonymousKey(map[string]interface{}{"application": "test-app"})
+	assert.ErrorIs(t, err, ErrMissingTargetKey)
+}
+
+func TestHashedAnonymousKey_NestedPath(t *testing.T) {
+	strategy := HashedAnonymousKey("user.email")
+	key, err := strategy.AnonymousKey(map[string]interface{}{
+		"user": map[string]interface{}{"email": "user@example.com"},
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, key)
+
+	_, err = strategy.AnonymousKey(map[string]interface{}{"user": "not a map"})
+	assert.ErrorIs(t, err, ErrMissingTargetKey)
+}
+
+func TestProcessAnonymousKey(t *testing.T) {
+	strategy := ProcessAnonymousKey()
+	first, err := strategy.AnonymousKey(map[string]interface{}{"application": "test-app", "environment": "test-env"})
+	assert.NoError(t, err)
+	second, err := strategy.AnonymousKey(map[string]interface{}{"ipAddress": "203.0.113.42"})
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	other, err := ProcessAnonymousKey().AnonymousKey(nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, other)
+}
+
+func TestProviderHook_BeforeTargetingKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy AnonymousKeyStrategy
+		evalCtx  openfeature.EvaluationContext
+		wantKey  string
+		wantErr  error
+	}{
+		{
+			name:    "explicit targeting key",
+			evalCtx: openfeature.NewEvaluationContext("user-123", nil),
+			wantKey: "user-123",
+		},
+		{
+			name: "user id",
+			evalCtx: openfeature.NewTargetlessEvaluationContext(map[string]interface{}{
+				"user": map[string]interface{}{"id": "user-456"},
+			}),
+			wantKey: "user-456",
+		},
+		{
+			name: "custom function",
+			strategy: AnonymousKeyFunc(func(attributes map[string]interface{}) (string, error) {
+				return "visitor-" + attributes["sessionId"].(string), nil
+			}),
+			evalCtx: openfeature.NewTargetlessEvaluationContext(map[string]interface{}{
+				"sessionId": "abc",
+			}),
+			wantKey: "visitor-abc",
+		},
+		{
+			name:     "rejected",
+			strategy: RejectAnonymous(),
+			evalCtx:  openfeature.NewTargetlessEvaluationContext(nil),
+			wantErr:  ErrMissingTargetKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Provider{
+				config: Config{
+					Application:  "test-app",
+					Environment:  "test-env",
+					AnonymousKey: tt.strategy,
+				},
+			}
+			hook := NewProviderHook(p)
+			hookCtx := openfeature.NewHookContext("test-flag", openfeature.Boolean, false, openfeature.ClientMetadata{}, openfeature.Metadata{}, tt.evalCtx)
+
+			got, err := hook.Before(context.Background(), hookCtx, openfeature.NewHookHints(nil))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantKey, got.TargetingKey())
+			assert.Equal(t, "test-app", got.Attribute("application"))
+		})
+	}
+}
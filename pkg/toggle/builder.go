@@ -0,0 +1,233 @@
+package toggle
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// ContextBuilder assembles an openfeature.EvaluationContext in the shape buildContext
+// expects, from fluent calls and from structs annotated with `toggle` tags.
+//
+// Keys follow the Hyphen context layout:
+//   - "targetingKey" and "ipAddress"
+//   - "user.id", "user.email" and "user.name"
+//   - "user.customAttributes.<name>" for user custom attributes
+//   - "<name>" for top-level custom attributes
+//
+// Any other key, such as "user.emial", is reported by Build as ErrUnknownContextKey.
+type ContextBuilder struct {
+	targetingKey string
+	attributes   map[string]interface{}
+	user         map[string]interface{}
+	userAttrs    map[string]interface{}
+	errs         []error
+}
+
+func NewContextBuilder() *ContextBuilder {
+	return &ContextBuilder{
+		attributes: make(map[string]interface{}),
+		user:       make(map[string]interface{}),
+		userAttrs:  make(map[string]interface{}),
+	}
+}
+
+func (b *ContextBuilder) TargetingKey(key string) *ContextBuilder {
+	return b.Set("targetingKey", key)
+}
+
+func (b *ContextBuilder) IPAddress(ip string) *ContextBuilder {
+	return b.Set("ipAddress", ip)
+}
+
+func (b *ContextBuilder) UserID(id string) *ContextBuilder {
+	return b.Set("user.id", id)
+}
+
+func (b *ContextBuilder) UserEmail(email string) *ContextBuilder {
+	return b.Set("user.email", email)
+}
+
+func (b *ContextBuilder) UserName(name string) *ContextBuilder {
+	return b.Set("user.name", name)
+}
+
+// UserAttribute sets a custom attribute on the user.
+func (b *ContextBuilder) UserAttribute(name string, value interface{}) *ContextBuilder {
+	return b.Set("user.customAttributes."+name, value)
+}
+
+// Attribute sets a top-level custom attribute.
+func (b *ContextBuilder) Attribute(name string, value interface{}) *ContextBuilder {
+	return b.Set(name, value)
+}
+
+// Set places value at key. Invalid keys and values are reported by Build.
+func (b *ContextBuilder) Set(key string, value interface{}) *ContextBuilder {
+	if err := b.set(key, value); err != nil {
+		b.errs = append(b.errs, err)
+	}
+	return b
+}
+
+// FromStruct copies the fields of v, a struct or pointer to struct, that carry a
+// `toggle` tag. The tag holds the key followed by optional ",omitempty" and
+// ",required" flags; "-" skips the field. Embedded structs without a tag are
+// walked recursively.
+//
+//	type Visitor struct {
+//	    ID    string `toggle:"targetingKey,required"`
+//	    Email string `toggle:"user.email,omitempty"`
+//	    Plan  string `toggle:"plan"`
+//	}
+func (b *ContextBuilder) FromStruct(v interface{}) *ContextBuilder {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			b.errs = append(b.errs, fmt.Errorf("%w: nil %T", ErrInvalidContextStruct, v))
+			return b
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		b.errs = append(b.errs, fmt.Errorf("%w: %T is not a struct", ErrInvalidContextStruct, v))
+		return b
+	}
+	b.fromStruct(rv)
+	return b
+}
+
+func (b *ContextBuilder) fromStruct(rv reflect.Value) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, tagged := field.Tag.Lookup("toggle")
+
+		if !tagged {
+			if field.Anonymous {
+				embedded := rv.Field(i)
+				if embedded.Kind() == reflect.Pointer {
+					if embedded.IsNil() {
+						continue
+					}
+					embedded = embedded.Elem()
+				}
+				if embedded.Kind() == reflect.Struct {
+					b.fromStruct(embedded)
+				}
+			}
+			continue
+		}
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		key, flags, _ := strings.Cut(tag, ",")
+		omitEmpty := strings.Contains(","+flags+",", ",omitempty,")
+		required := strings.Contains(","+flags+",", ",required,")
+
+		value := rv.Field(i)
+		if value.IsZero() {
+			if required {
+				b.errs = append(b.errs, fmt.Errorf("%w: %s (%s.%s)", ErrMissingContextField, key, rt.Name(), field.Name))
+				continue
+			}
+			if omitEmpty {
+				continue
+			}
+		}
+		for value.Kind() == reflect.Pointer && !value.IsNil() {
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.Pointer {
+			continue
+		}
+
+		if err := b.set(key, value.Interface()); err != nil {
+			b.errs = append(b.errs, fmt.Errorf("%s.%s: %w", rt.Name(), field.Name, err))
+		}
+	}
+}
+
+// Build returns the evaluation context, or every problem found while building it
+// joined into a single error.
+func (b *ContextBuilder) Build() (openfeature.EvaluationContext, error) {
+	if len(b.errs) > 0 {
+		return openfeature.EvaluationContext{}, errors.Join(b.errs...)
+	}
+
+	attributes := make(map[string]interface{}, len(b.attributes)+1)
+	for k, v := range b.attributes {
+		attributes[k] = v
+	}
+	if len(b.user) > 0 || len(b.userAttrs) > 0 {
+		user := make(map[string]interface{}, len(b.user)+1)
+		for k, v := range b.user {
+			user[k] = v
+		}
+		if len(b.userAttrs) > 0 {
+			customAttrs := make(map[string]interface{}, len(b.userAttrs))
+			for k, v := range b.userAttrs {
+				customAttrs[k] = v
+			}
+			user["customAttributes"] = customAttrs
+		}
+		attributes["user"] = user
+	}
+
+	if b.targetingKey == "" {
+		return openfeature.NewTargetlessEvaluationContext(attributes), nil
+	}
+	return openfeature.NewEvaluationContext(b.targetingKey, attributes), nil
+}
+
+func (b *ContextBuilder) set(key string, value interface{}) error {
+	switch key {
+	case "targetingKey":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: targetingKey must be a string, got %T", ErrInvalidContextValue, value)
+		}
+		b.targetingKey = s
+		return nil
+	case "ipAddress":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%w: ipAddress must be a string, got %T", ErrInvalidContextValue, value)
+		}
+		b.attributes[key] = value
+		return nil
+	case "user.id", "user.email", "user.name":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%w: %s must be a string, got %T", ErrInvalidContextValue, key, value)
+		}
+		b.user[strings.TrimPrefix(key, "user.")] = value
+		return nil
+	}
+
+	if name, ok := strings.CutPrefix(key, "user.customAttributes."); ok && validAttributeName(name) {
+		b.userAttrs[name] = value
+		return nil
+	}
+	if validAttributeName(key) && !isReservedAttribute(key) {
+		b.attributes[key] = value
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownContextKey, key)
+}
+
+func validAttributeName(name string) bool {
+	return name != "" && !strings.Contains(name, ".")
+}
+
+// isReservedAttribute reports whether name is set by the provider or has a
+// dedicated builder key, so it cannot be used as a top-level custom attribute.
+func isReservedAttribute(name string) bool {
+	switch name {
+	case "user", "customAttributes", "application", "environment":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package toggle
+
+import (
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextBuilder_Fluent(t *testing.T) {
+	evalCtx, err := NewContextBuilder().
+		TargetingKey("user-123").
+		IPAddress("203.0.113.42").
+		UserID("user-123").
+		UserEmail("user@example.com").
+		UserName("John Doe").
+		UserAttribute("role", "admin").
+		Attribute("subscriptionLevel", "premium").
+		Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "user-123", evalCtx.TargetingKey())
+	assert.Equal(t, map[string]interface{}{
+		"ipAddress":         "203.0.113.42",
+		"subscriptionLevel": "premium",
+		"user": map[string]interface{}{
+			"id":    "user-123",
+			"email": "user@example.com",
+			"name":  "John Doe",
+			"customAttributes": map[string]interface{}{
+				"role": "admin",
+			},
+		},
+	}, evalCtx.Attributes())
+
+	p := &Provider{config: Config{Application: "test-app", Environment: "test-env"}}
+	hyphenCtx, err := p.buildContext(flattenEvaluationContext(evalCtx))
+	assert.NoError(t, err)
+	assert.Equal(t, "203.0.113.42", hyphenCtx.IPAddress)
+	assert.Equal(t, "user@example.com", hyphenCtx.User.Email)
+	assert.Equal(t, map[string]interface{}{"role": "admin"}, hyphenCtx.User.CustomAttributes)
+	assert.Equal(t, map[string]interface{}{"subscriptionLevel": "premium"}, hyphenCtx.CustomAttributes)
+}
+
+type builderAccount struct {
+	Plan  string `toggle:"plan"`
+	Seats int    `toggle:"seats,omitempty"`
+}
+
+type builderVisitor struct {
+	builderAccount
+	ID       string  `toggle:"targetingKey,required"`
+	Email    string  `toggle:"user.email,omitempty"`
+	Role     *string `toggle:"user.customAttributes.role"`
+	Internal string  `toggle:"-"`
+	Ignored  string
+}
+
+func TestContextBuilder_FromStruct(t *testing.T) {
+	role := "admin"
+	evalCtx, err := NewContextBuilder().
+		FromStruct(&builderVisitor{
+			builderAccount: builderAccount{Plan: "pro"},
+			ID:             "user-123",
+			Role:           &role,
+			Internal:       "secret",
+			Ignored:        "ignored",
+		}).
+		Attribute("region", "us-east").
+		Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "user-123", evalCtx.TargetingKey())
+	assert.Equal(t, map[string]interface{}{
+		"plan":   "pro",
+		"region": "us-east",
+		"user": map[string]interface{}{
+			"customAttributes": map[string]interface{}{"role": "admin"},
+		},
+	}, evalCtx.Attributes())
+}
+
+func TestContextBuilder_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		build   func(b *ContextBuilder) *ContextBuilder
+		wantErr []error
+	}{
+		{
+			name: "unknown keys",
+			build: func(b *ContextBuilder) *ContextBuilder {
+				return b.Set("user.emial", "user@example.com").
+					Set("customAttributes", map[string]interface{}{}).
+					Set("account.plan", "pro")
+			},
+			wantErr: []error{ErrUnknownContextKey},
+		},
+		{
+			name: "invalid values",
+			build: func(b *ContextBuilder) *ContextBuilder {
+				return b.Set("user.id", 42).Set("targetingKey", true)
+			},
+			wantErr: []error{ErrInvalidContextValue},
+		},
+		{
+			name: "missing required field and unknown tag",
+			build: func(b *ContextBuilder) *ContextBuilder {
+				return b.FromStruct(struct {
+					ID   string `toggle:"targetingKey,required"`
+					Plan string `toggle:"account.plan"`
+				}{Plan: "pro"})
+			},
+			wantErr: []error{ErrMissingContextField, ErrUnknownContextKey},
+		},
+		{
+			name: "not a struct",
+			build: func(b *ContextBuilder) *ContextBuilder {
+				return b.FromStruct("user-123")
+			},
+			wantErr: []error{ErrInvalidContextStruct},
+		},
+		{
+			name: "nil struct",
+			build: func(b *ContextBuilder) *ContextBuilder {
+				return b.FromStruct((*builderVisitor)(nil))
+			},
+			wantErr: []error{ErrInvalidContextStruct},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evalCtx, err := tt.build(NewContextBuilder()).Build()
+			for _, want := range tt.wantErr {
+				assert.ErrorIs(t, err, want)
+			}
+			assert.Equal(t, openfeature.EvaluationContext{}, evalCtx)
+		})
+	}
+}
+
+func TestContextBuilder_Targetless(t *testing.T) {
+	evalCtx, err := NewContextBuilder().Attribute("plan", "free").Build()
+	assert.NoError(t, err)
+	assert.Empty(t, evalCtx.TargetingKey())
+	assert.Equal(t, map[string]interface{}{"plan": "free"}, evalCtx.Attributes())
+}
@@ -0,0 +1,159 @@
+package toggle
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// FlagResolution is the resolved state of a single flag returned by EvaluateAll.
+// It is JSON serializable so it can be handed to front-end applications or stored for auditing.
+type FlagResolution struct {
+	Key          string                   `json:"key"`
+	Type         openfeature.Type         `json:"-"`
+	Value        interface{}              `json:"value"`
+	Reason       openfeature.Reason       `json:"reason"`
+	Variant      string                   `json:"variant,omitempty"`
+	FlagMetadata openfeature.FlagMetadata `json:"flagMetadata,omitempty"`
+	ErrorCode    openfeature.ErrorCode    `json:"errorCode,omitempty"`
+	ErrorMessage string                   `json:"errorMessage,omitempty"`
+}
+
+// MarshalJSON encodes Type using the Horizon type names.
+func (r FlagResolution) MarshalJSON() ([]byte, error) {
+	type alias FlagResolution
+	return json.Marshal(struct {
+		alias
+		Type string `json:"type"`
+	}{
+		alias: alias(r),
+		Type:  typeToString[r.Type],
+	})
+}
+
+// EvaluateAll resolves every flag Horizon returns for evalCtx in a single request.
+// Numbers are returned as int64 when they are integral and fit, otherwise as float64.
+// Per-flag failures are reported in the flag's ErrorCode; the returned error is only
+// set when the context is invalid or Horizon could not be reached. The transaction
+// context stored in ctx, for example by ContextMiddleware, is merged under evalCtx.
+// Overrides apply to the flags Horizon returns. A context without a targeting key
+// is keyed like an evaluation: by the user id, or by the anonymous key strategy.
+func (p *Provider) EvaluateAll(ctx context.Context, evalCtx openfeature.EvaluationContext) (map[string]FlagResolution, error) {
+	flat := flattenEvaluationContext(mergeTransactionContext(ctx, evalCtx))
+	if _, ok := flat[openfeature.TargetingKey].(string); !ok {
+		config := p.settings()
+		attributes := make(map[string]interface{}, len(flat)+2)
+		for k, v := range flat {
+			attributes[k] = v
+		}
+		attributes["application"] = config.Application
+		attributes["environment"] = config.Environment
+		key, err := p.resolveTargetingKey(attributes)
+		if err != nil {
+			return nil, err
+		}
+		flat[openfeature.TargetingKey] = key
+	}
+	hyphenCtx, err := p.buildContext(flat)
+	if err != nil {
+		return nil, err
+	}
+
+	eval, err := p.client.Evaluate(hyphenCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]FlagResolution, len(eval.Toggles))
+	for key := range eval.Toggles {
+		if toggle, scope, ok := p.override(ctx, key, flat); ok {
+			overridden := &Response{Toggles: map[string]Evaluation{key: toggle}}
+			results[key] = resolveAny(overridden, key).withDetail(overrideDetail(scope))
+			continue
+		}
+		results[key] = resolveAny(eval, key)
+	}
+	return results, nil
+}
+
+// resolveAny resolves flag with the coercer matching the type Horizon declared for it.
+func resolveAny(eval *Response, flag string) FlagResolution {
+	result := FlagResolution{Key: flag}
+
+	toggle, resErr, ok := lookupToggle(eval, flag)
+	if !ok {
+		return result.withDetail(errorDetail(resErr))
+	}
+
+	var (
+		value interface{}
+		err   error
+	)
+	switch toggle.Type {
+	case "boolean":
+		result.Type = openfeature.Boolean
+		value, err = coerceBool(toggle)
+	case "string":
+		result.Type = openfeature.String
+		value, err = coerceString(toggle)
+	case "number":
+		result.Type = openfeature.Int
+		value, err = coerceInt(RoundingReject)(toggle)
+		if err != nil {
+			result.Type = openfeature.Float
+			value, err = coerceFloat(toggle)
+		}
+	case "object":
+		result.Type = openfeature.Object
+		value, err = coerceObject(toggle)
+	default:
+		err = ErrInvalidFlagType
+	}
+	if err != nil {
+		return result.withDetail(errorDetail(openfeature.NewTypeMismatchResolutionError(err.Error())))
+	}
+
+	result.Value = value
+	return result.withDetail(resolutionDetail(eval, toggle))
+}
+
+func (r FlagResolution) withDetail(detail openfeature.ProviderResolutionDetail) FlagResolution {
+	resolution := detail.ResolutionDetail()
+	r.Reason = resolution.Reason
+	r.Variant = resolution.Variant
+	r.ErrorCode = resolution.ErrorCode
+	r.ErrorMessage = resolution.ErrorMessage
+	if len(resolution.FlagMetadata) > 0 {
+		r.FlagMetadata = resolution.FlagMetadata
+	}
+	return r
+}
+
+// flattenEvaluationContext mirrors how the OpenFeature client flattens a context
+// before handing it to a provider.
+func flattenEvaluationContext(evalCtx openfeature.EvaluationContext) openfeature.FlattenedContext {
+	flat := openfeature.FlattenedContext{}
+	for k, v := range evalCtx.Attributes() {
+		flat[k] = v
+	}
+	if evalCtx.TargetingKey() != "" {
+		flat[openfeature.TargetingKey] = evalCtx.TargetingKey()
+	}
+	return flat
+}
+
+// unflattenEvaluationContext rebuilds the EvaluationContext a flattened context
+// came from, so the hooks of wrapped providers can be run on it.
+func unflattenEvaluationContext(flat openfeature.FlattenedContext) openfeature.EvaluationContext {
+	attributes := make(map[string]interface{}, len(flat))
+	for k, v := range flat {
+		if k != openfeature.TargetingKey {
+			attributes[k] = v
+		}
+	}
+	if key, ok := flat[openfeature.TargetingKey].(string); ok && key != "" {
+		return openfeature.NewEvaluationContext(key, attributes)
+	}
+	return openfeature.NewTargetlessEvaluationContext(attributes)
+}
@@ -0,0 +1,135 @@
+package toggle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvider_EvaluateAll(t *testing.T) {
+	var gotCtx EvaluationContext
+	p := &Provider{
+		client: &MockClient{
+			EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+				gotCtx = ctx
+				return &Response{Toggles: map[string]Evaluation{
+					"bool-flag":   {Key: "bool-flag", Type: "boolean", Value: true, Reason: "split", Variant: "on"},
+					"string-flag": {Key: "string-flag", Type: "string", Value: "blue"},
+					"int-flag":    {Key: "int-flag", Type: "number", Value: json.Number("9007199254740993")},
+					"float-flag":  {Key: "float-flag", Type: "number", Value: json.Number("0.5")},
+					"object-flag": {Key: "object-flag", Type: "object", Value: map[string]interface{}{"a": json.Number("1")}},
+					"bad-flag":    {Key: "bad-flag", Type: "boolean", Value: "yes"},
+					"failed-flag": {Key: "failed-flag", Type: "string", Error: "flag not found"},
+				}}, nil
+			},
+		},
+		config: Config{
+			Application: "test-app",
+			Environment: "test-env",
+		},
+	}
+
+	evalCtx := openfeature.NewEvaluationContext("user-123", map[string]interface{}{"plan": "premium"})
+	results, err := p.EvaluateAll(context.Background(), evalCtx)
+	assert.NoError(t, err)
+	assert.Len(t, results, 7)
+	assert.Equal(t, "user-123", gotCtx.TargetingKey)
+	assert.Equal(t, "premium", gotCtx.CustomAttributes["plan"])
+
+	assert.Equal(t, true, results["bool-flag"].Value)
+	assert.Equal(t, openfeature.Boolean, results["bool-flag"].Type)
+	assert.Equal(t, openfeature.SplitReason, results["bool-flag"].Reason)
+	assert.Equal(t, "on", results["bool-flag"].Variant)
+
+	assert.Equal(t, "blue", results["string-flag"].Value)
+	assert.Equal(t, int64(9007199254740993), results["int-flag"].Value)
+	assert.Equal(t, openfeature.Int, results["int-flag"].Type)
+	assert.Equal(t, 0.5, results["float-flag"].Value)
+	assert.Equal(t, openfeature.Float, results["float-flag"].Type)
+	assert.Equal(t, map[string]interface{}{"a": 1.0}, results["object-flag"].Value)
+
+	assert.Equal(t, openfeature.TypeMismatchCode, results["bad-flag"].ErrorCode)
+	assert.Nil(t, results["bad-flag"].Value)
+	assert.Equal(t, openfeature.FlagNotFoundCode, results["failed-flag"].ErrorCode)
+	assert.Equal(t, openfeature.ErrorReason, results["failed-flag"].Reason)
+
+	data, err := json.Marshal(results["bool-flag"])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"key":"bool-flag","type":"boolean","value":true,"reason":"SPLIT","variant":"on","flagMetadata":{"cached":false}}`, string(data))
+}
+
+func TestProvider_EvaluateAllErrors(t *testing.T) {
+	p := &Provider{
+		client: &MockClient{
+			EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+				return nil, errors.New("unreachable")
+			},
+		},
+		config: Config{
+			Application:  "test-app",
+			Environment:  "test-env",
+			AnonymousKey: RejectAnonymous(),
+		},
+	}
+
+	_, err := p.EvaluateAll(context.Background(), openfeature.NewTargetlessEvaluationContext(nil))
+	assert.ErrorIs(t, err, ErrMissingTargetKey)
+
+	_, err = p.EvaluateAll(context.Background(), openfeature.NewEvaluationContext("user-123", nil))
+	assert.EqualError(t, err, "unreachable")
+}
+
+func TestProvider_EvaluateAllTargetingKeyFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		evalCtx openfeature.EvaluationContext
+		want    string
+	}{
+		{
+			name: "user id",
+			evalCtx: openfeature.NewTargetlessEvaluationContext(map[string]interface{}{
+				"user": map[string]interface{}{"id": "user-123"},
+			}),
+			want: "user-123",
+		},
+		{
+			name: "anonymous key strategy",
+			evalCtx: openfeature.NewTargetlessEvaluationContext(map[string]interface{}{
+				"ip": "10.0.0.1",
+			}),
+			want: "anon-10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCtx EvaluationContext
+			p := &Provider{
+				client: &MockClient{
+					EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+						gotCtx = ctx
+						return &Response{Toggles: map[string]Evaluation{
+							"flag": {Key: "flag", Type: "boolean", Value: true},
+						}}, nil
+					},
+				},
+				config: Config{
+					Application: "test-app",
+					Environment: "test-env",
+					AnonymousKey: AnonymousKeyFunc(func(attributes map[string]interface{}) (string, error) {
+						return "anon-" + attributes["ip"].(string), nil
+					}),
+				},
+			}
+
+			results, err := p.EvaluateAll(context.Background(), tt.evalCtx)
+			assert.NoError(t, err)
+			assert.Equal(t, true, results["flag"].Value)
+			assert.Equal(t, tt.want, gotCtx.TargetingKey)
+		})
+	}
+}
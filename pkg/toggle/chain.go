@@ -0,0 +1,221 @@
+package toggle
+
+import (
+	"context"
+	"errors"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Keys set on the FlagMetadata of ChainProvider resolutions.
+const (
+	// MetadataKeyProvider is the name of the provider that answered.
+	MetadataKeyProvider = "provider"
+	// MetadataKeyProviderIndex is the position in the chain of the provider that answered.
+	MetadataKeyProviderIndex = "providerIndex"
+)
+
+// FallbackPolicy lists the error codes that move an evaluation on to the next
+// provider of a chain. Errors with other codes are returned as they are.
+type FallbackPolicy map[openfeature.ErrorCode]bool
+
+// ChainConfig configures a ChainProvider.
+type ChainConfig struct {
+	// Providers are tried in order, for example the Hyphen Provider, then a
+	// legacy flag system, then a LocalProvider with defaults.
+	Providers []openfeature.FeatureProvider
+	// Fallback selects the errors that fall through to the next provider. When
+	// nil, every error does.
+	Fallback FallbackPolicy
+}
+
+// ChainProvider evaluates flags through an ordered chain of providers, moving to
+// the next provider when one fails with an error its FallbackPolicy lists. The
+// result of the last provider tried is returned, with its name and position in
+// FlagMetadata.
+//
+// The OpenFeature client does not see the hooks of the chained providers, so the
+// chain runs them itself. Each provider's Before hooks prepare a separate copy of
+// the context, and a provider whose Before hooks fail is skipped. After hooks run
+// only for the provider that answered, so a fallback answer is not reported as a
+// Hyphen evaluation.
+type ChainProvider struct {
+	providers []openfeature.FeatureProvider
+	fallback  FallbackPolicy
+}
+
+func NewChainProvider(config ChainConfig) (*ChainProvider, error) {
+	if len(config.Providers) == 0 {
+		return nil, ErrEmptyChain
+	}
+
+	return &ChainProvider{
+		providers: append([]openfeature.FeatureProvider(nil), config.Providers...),
+		fallback:  config.Fallback,
+	}, nil
+}
+
+func (c *ChainProvider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{
+		Name: "hyphen-chain-provider",
+	}
+}
+
+// Hooks returns no hooks: the chain runs the hooks of its providers during evaluation.
+func (c *ChainProvider) Hooks() []openfeature.Hook {
+	return nil
+}
+
+// Init initializes the providers of the chain that need it. It fails only when
+// every provider fails, since the others can still answer.
+func (c *ChainProvider) Init(evalCtx openfeature.EvaluationContext) error {
+	var errs []error
+	for _, p := range c.providers {
+		if handler, ok := p.(openfeature.StateHandler); ok {
+			if err := handler.Init(evalCtx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) == len(c.providers) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+func (c *ChainProvider) Shutdown() {
+	for _, p := range c.providers {
+		if handler, ok := p.(openfeature.StateHandler); ok {
+			handler.Shutdown()
+		}
+	}
+}
+
+func (c *ChainProvider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	value, detail := chainResolve(ctx, c, flag, openfeature.Boolean, defaultValue, evalCtx, func(p openfeature.FeatureProvider, evalCtx openfeature.FlattenedContext) (bool, openfeature.ProviderResolutionDetail) {
+		res := p.BooleanEvaluation(ctx, flag, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.BoolResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+func (c *ChainProvider) StringEvaluation(ctx context.Context, flag string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	value, detail := chainResolve(ctx, c, flag, openfeature.String, defaultValue, evalCtx, func(p openfeature.FeatureProvider, evalCtx openfeature.FlattenedContext) (string, openfeature.ProviderResolutionDetail) {
+		res := p.StringEvaluation(ctx, flag, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.StringResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+func (c *ChainProvider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	value, detail := chainResolve(ctx, c, flag, openfeature.Float, defaultValue, evalCtx, func(p openfeature.FeatureProvider, evalCtx openfeature.FlattenedContext) (float64, openfeature.ProviderResolutionDetail) {
+		res := p.FloatEvaluation(ctx, flag, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.FloatResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+func (c *ChainProvider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	value, detail := chainResolve(ctx, c, flag, openfeature.Int, defaultValue, evalCtx, func(p openfeature.FeatureProvider, evalCtx openfeature.FlattenedContext) (int64, openfeature.ProviderResolutionDetail) {
+		res := p.IntEvaluation(ctx, flag, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.IntResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+func (c *ChainProvider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	value, detail := chainResolve(ctx, c, flag, openfeature.Object, defaultValue, evalCtx, func(p openfeature.FeatureProvider, evalCtx openfeature.FlattenedContext) (interface{}, openfeature.ProviderResolutionDetail) {
+		res := p.ObjectEvaluation(ctx, flag, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.InterfaceResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// Track forwards the event to every provider of the chain that supports tracking.
+func (c *ChainProvider) Track(ctx context.Context, trackingEventName string, evalCtx openfeature.EvaluationContext, details openfeature.TrackingEventDetails) {
+	for _, p := range c.providers {
+		if tracker, ok := p.(openfeature.Tracker); ok {
+			tracker.Track(ctx, trackingEventName, evalCtx, details)
+		}
+	}
+}
+
+// chainResolve runs evaluate against each provider in turn, with the context its
+// Before hooks prepared, until one answers without an error the fallback policy
+// lists. Providers whose Before hooks fail are skipped.
+func chainResolve[T any](ctx context.Context, c *ChainProvider, flag string, flagType openfeature.Type, defaultValue T, evalCtx openfeature.FlattenedContext, evaluate func(p openfeature.FeatureProvider, evalCtx openfeature.FlattenedContext) (T, openfeature.ProviderResolutionDetail)) (T, openfeature.ProviderResolutionDetail) {
+	value, detail := defaultValue, openfeature.ProviderResolutionDetail{}
+	for i, p := range c.providers {
+		hookContext := openfeature.NewHookContext(flag, flagType, defaultValue, openfeature.ClientMetadata{}, p.Metadata(), unflattenEvaluationContext(evalCtx))
+		providerCtx, err := runBeforeHooks(ctx, p, hookContext, openfeature.HookHints{})
+		hookContext = openfeature.NewHookContext(flag, flagType, defaultValue, openfeature.ClientMetadata{}, p.Metadata(), providerCtx)
+		if err != nil {
+			value, detail = defaultValue, withProvider(errorDetail(openfeature.NewGeneralResolutionError(err.Error())), i, p)
+			finishHooks(ctx, p, hookContext, err)
+			continue
+		}
+
+		value, detail = evaluate(p, flattenEvaluationContext(providerCtx))
+		detail = withProvider(detail, i, p)
+		if code := detail.ResolutionDetail().ErrorCode; code != "" {
+			finishHooks(ctx, p, hookContext, detail.ResolutionError)
+			if i < len(c.providers)-1 && c.fallsBack(code) {
+				continue
+			}
+			return value, detail
+		}
+
+		details := openfeature.InterfaceEvaluationDetails{
+			Value: value,
+			EvaluationDetails: openfeature.EvaluationDetails{
+				FlagKey:          flag,
+				FlagType:         flagType,
+				ResolutionDetail: detail.ResolutionDetail(),
+			},
+		}
+		hooks := p.Hooks()
+		for j := len(hooks) - 1; j >= 0; j-- {
+			if err := hooks[j].After(ctx, hookContext, details, openfeature.HookHints{}); err != nil {
+				logError(ctx, "Error in hook:", err)
+				finishHooks(ctx, p, hookContext, err)
+				return value, detail
+			}
+		}
+		finishHooks(ctx, p, hookContext, nil)
+		return value, detail
+	}
+	return value, detail
+}
+
+// finishHooks runs the Error hooks of p when err is set, then its Finally hooks,
+// in reverse order as the OpenFeature client does.
+func finishHooks(ctx context.Context, p openfeature.FeatureProvider, hookContext openfeature.HookContext, err error) {
+	hooks := p.Hooks()
+	if err != nil {
+		for i := len(hooks) - 1; i >= 0; i-- {
+			hooks[i].Error(ctx, hookContext, err, openfeature.HookHints{})
+		}
+	}
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].Finally(ctx, hookContext, openfeature.HookHints{})
+	}
+}
+
+func (c *ChainProvider) fallsBack(code openfeature.ErrorCode) bool {
+	if c.fallback == nil {
+		return true
+	}
+	return c.fallback[code]
+}
+
+// withProvider records the answering provider in detail's FlagMetadata.
+func withProvider(detail openfeature.ProviderResolutionDetail, index int, p openfeature.FeatureProvider) openfeature.ProviderResolutionDetail {
+	metadata := make(openfeature.FlagMetadata, len(detail.FlagMetadata)+2)
+	for k, v := range detail.FlagMetadata {
+		metadata[k] = v
+	}
+	metadata[MetadataKeyProvider] = p.Metadata().Name
+	metadata[MetadataKeyProviderIndex] = index
+	detail.FlagMetadata = metadata
+	return detail
+}
@@ -0,0 +1,245 @@
+package toggle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func newChainPrimary(evaluate func(ctx EvaluationContext) (*Response, error)) *Provider {
+	enableUsage := false
+	return &Provider{
+		client: &MockClient{EvaluateFunc: evaluate},
+		config: Config{
+			Application: "test-app",
+			Environment: "test-env",
+			EnableUsage: &enableUsage,
+		},
+	}
+}
+
+func TestNewChainProvider(t *testing.T) {
+	_, err := NewChainProvider(ChainConfig{})
+	assert.ErrorIs(t, err, ErrEmptyChain)
+}
+
+func TestChainProvider_Evaluation(t *testing.T) {
+	primary := newChainPrimary(func(ctx EvaluationContext) (*Response, error) {
+		return &Response{Toggles: map[string]Evaluation{
+			"hyphen-flag":  {Key: "hyphen-flag", Type: "string", Value: "hyphen"},
+			"invalid-flag": {Key: "invalid-flag", Type: "boolean", Value: true},
+		}}, nil
+	})
+	failing := newChainPrimary(func(ctx EvaluationContext) (*Response, error) {
+		return nil, errors.New("horizon unavailable")
+	})
+	legacy, err := NewLocalProvider(map[string]interface{}{"hyphen-flag": "legacy", "legacy-flag": "legacy", "invalid-flag": "legacy"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		primary   *Provider
+		fallback  FallbackPolicy
+		flag      string
+		want      string
+		wantCode  openfeature.ErrorCode
+		wantIndex int
+	}{
+		{
+			name:      "primary answers",
+			primary:   primary,
+			flag:      "hyphen-flag",
+			want:      "hyphen",
+			wantIndex: 0,
+		},
+		{
+			name:      "flag not found falls back",
+			primary:   primary,
+			flag:      "legacy-flag",
+			want:      "legacy",
+			wantIndex: 1,
+		},
+		{
+			name:      "general error falls back",
+			primary:   failing,
+			flag:      "hyphen-flag",
+			want:      "legacy",
+			wantIndex: 1,
+		},
+		{
+			name:      "policy limits fallback",
+			primary:   failing,
+			fallback:  FallbackPolicy{openfeature.FlagNotFoundCode: true},
+			flag:      "hyphen-flag",
+			want:      "default",
+			wantCode:  openfeature.GeneralCode,
+			wantIndex: 0,
+		},
+		{
+			name:      "type mismatch outside policy",
+			primary:   primary,
+			fallback:  FallbackPolicy{openfeature.FlagNotFoundCode: true},
+			flag:      "invalid-flag",
+			want:      "default",
+			wantCode:  openfeature.TypeMismatchCode,
+			wantIndex: 0,
+		},
+		{
+			name:      "last provider error is returned",
+			primary:   primary,
+			flag:      "missing-flag",
+			want:      "default",
+			wantCode:  openfeature.FlagNotFoundCode,
+			wantIndex: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := NewChainProvider(ChainConfig{
+				Providers: []openfeature.FeatureProvider{tt.primary, legacy},
+				Fallback:  tt.fallback,
+			})
+			assert.NoError(t, err)
+
+			res := chain.StringEvaluation(context.Background(), tt.flag, "default", openfeature.FlattenedContext{"targetingKey": "user-1"})
+			assert.Equal(t, tt.want, res.Value)
+			assert.Equal(t, tt.wantCode, res.ResolutionDetail().ErrorCode)
+			assert.Equal(t, tt.wantIndex, res.FlagMetadata[MetadataKeyProviderIndex])
+			names := []string{tt.primary.Metadata().Name, legacy.Metadata().Name}
+			assert.Equal(t, names[tt.wantIndex], res.FlagMetadata[MetadataKeyProvider])
+		})
+	}
+}
+
+func TestChainProvider_Hooks(t *testing.T) {
+	var reported []string
+	enableUsage := true
+	primary := &Provider{
+		client: &MockClient{
+			EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+				return &Response{Toggles: map[string]Evaluation{
+					"hyphen-flag": {Key: "hyphen-flag", Type: "boolean", Value: true},
+				}}, nil
+			},
+			SendTelemetryFunc: func(payload TelemetryPayload) error {
+				reported = append(reported, payload.Data.Toggle.Key)
+				return nil
+			},
+		},
+		config: Config{
+			Application: "test-app",
+			Environment: "test-env",
+			EnableUsage: &enableUsage,
+		},
+	}
+	primary.hooks = []openfeature.Hook{NewProviderHook(primary)}
+	legacy, err := NewLocalProvider(map[string]interface{}{"legacy-flag": true})
+	assert.NoError(t, err)
+
+	chain, err := NewChainProvider(ChainConfig{Providers: []openfeature.FeatureProvider{primary, legacy}})
+	assert.NoError(t, err)
+	assert.NoError(t, openfeature.SetNamedProviderAndWait("chain-test", chain))
+	client := openfeature.NewClient("chain-test")
+
+	details, err := client.BooleanValueDetails(context.Background(), "hyphen-flag", false, openfeature.NewEvaluationContext("user-1", nil))
+	assert.NoError(t, err)
+	assert.True(t, details.Value)
+	assert.Equal(t, "hyphen-provider", details.FlagMetadata[MetadataKeyProvider])
+
+	details, err = client.BooleanValueDetails(context.Background(), "legacy-flag", false, openfeature.NewEvaluationContext("user-1", nil))
+	assert.NoError(t, err)
+	assert.True(t, details.Value)
+	assert.Equal(t, legacy.Metadata().Name, details.FlagMetadata[MetadataKeyProvider])
+
+	// Only the evaluation Hyphen answered is reported to Hyphen.
+	assert.Equal(t, []string{"hyphen-flag"}, reported)
+}
+
+// contextRecorder is a hook that records the contexts its provider is evaluated with.
+type contextRecorder struct {
+	openfeature.UnimplementedHook
+	contexts []openfeature.EvaluationContext
+}
+
+func (h *contextRecorder) Before(ctx context.Context, hookContext openfeature.HookContext, hookHints openfeature.HookHints) (*openfeature.EvaluationContext, error) {
+	h.contexts = append(h.contexts, hookContext.EvaluationContext())
+	return nil, nil
+}
+
+// hookedProvider adds hooks to a provider that has none.
+type hookedProvider struct {
+	openfeature.FeatureProvider
+	hooks []openfeature.Hook
+}
+
+func (p hookedProvider) Hooks() []openfeature.Hook {
+	return p.hooks
+}
+
+func TestChainProvider_SkipsProviderWithFailingHooks(t *testing.T) {
+	var evaluated int
+	primary, err := NewProviderWithClient(Config{
+		PublicKey:    testPublicKey,
+		Application:  "test-app",
+		Environment:  "test-env",
+		AnonymousKey: RejectAnonymous(),
+	}, &MockClient{EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+		evaluated++
+		return &Response{Toggles: map[string]Evaluation{
+			"flag": {Key: "flag", Type: "boolean", Value: true},
+		}}, nil
+	}})
+	assert.NoError(t, err)
+	local, err := NewLocalProvider(map[string]interface{}{"flag": false})
+	assert.NoError(t, err)
+	recorder := &contextRecorder{}
+	legacy := hookedProvider{FeatureProvider: local, hooks: []openfeature.Hook{recorder}}
+
+	chain, err := NewChainProvider(ChainConfig{
+		Providers: []openfeature.FeatureProvider{primary, legacy},
+		Fallback:  FallbackPolicy{openfeature.FlagNotFoundCode: true},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, openfeature.SetNamedProviderAndWait("chain-skip-test", chain))
+	client := openfeature.NewClient("chain-skip-test")
+
+	// Hyphen rejects the anonymous context, so the legacy provider answers even
+	// though the fallback policy does not list GENERAL errors.
+	details, err := client.BooleanValueDetails(context.Background(), "flag", true, openfeature.NewTargetlessEvaluationContext(map[string]interface{}{"plan": "pro"}))
+	assert.NoError(t, err)
+	assert.False(t, details.Value)
+	assert.Equal(t, 1, details.FlagMetadata[MetadataKeyProviderIndex])
+	assert.Zero(t, evaluated)
+
+	// Hyphen's hooks do not add their attributes to the legacy context.
+	assert.Len(t, recorder.contexts, 1)
+	assert.Equal(t, map[string]interface{}{"plan": "pro"}, recorder.contexts[0].Attributes())
+
+	details, err = client.BooleanValueDetails(context.Background(), "flag", false, openfeature.NewEvaluationContext("user-1", nil))
+	assert.NoError(t, err)
+	assert.True(t, details.Value)
+	assert.Equal(t, 0, details.FlagMetadata[MetadataKeyProviderIndex])
+	assert.Len(t, recorder.contexts, 1)
+}
+
+func TestChainProvider_AllHooksFail(t *testing.T) {
+	primary, err := NewProviderWithClient(Config{
+		PublicKey:    testPublicKey,
+		Application:  "test-app",
+		Environment:  "test-env",
+		AnonymousKey: RejectAnonymous(),
+	}, &MockClient{})
+	assert.NoError(t, err)
+
+	chain, err := NewChainProvider(ChainConfig{Providers: []openfeature.FeatureProvider{primary}})
+	assert.NoError(t, err)
+
+	res := chain.BooleanEvaluation(context.Background(), "flag", true, openfeature.FlattenedContext{})
+	assert.True(t, res.Value)
+	assert.Equal(t, openfeature.GeneralCode, res.ResolutionDetail().ErrorCode)
+	assert.Equal(t, 0, res.FlagMetadata[MetadataKeyProviderIndex])
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
@@ -15,13 +16,31 @@ type ClientInterface interface {
 	SendTelemetry(payload TelemetryPayload) error
 }
 
+// TrackingClient is implemented by clients that can deliver custom tracking
+// events to Horizon, as Client does. Tracking events recorded with a client that
+// does not implement it only reach the TrackingSinks.
+type TrackingClient interface {
+	SendTrackingEvent(payload TrackingPayload) error
+}
+
 type Client struct {
 	httpClient *http.Client
-	cache      *cache.Cache
-	config     Config
-	publicKey  string
-	keyGen     func(ctx EvaluationContext) string
-	endpoints  []HorizonEndpoints
+
+	// mu guards the fields below, which reconfigure replaces.
+	mu        sync.RWMutex
+	cache     *cache.Cache
+	config    Config
+	publicKey string
+	keyGen    func(ctx EvaluationContext) string
+	endpoints []HorizonEndpoints
+}
+
+// clientSettings is a consistent snapshot of the reconfigurable Client fields.
+type clientSettings struct {
+	cache     *cache.Cache
+	publicKey string
+	keyGen    func(ctx EvaluationContext) string
+	endpoints []HorizonEndpoints
 }
 
 func newClient(config Config, endpoints []HorizonEndpoints) (*Client, error) {
@@ -37,36 +56,91 @@ func newClient(config Config, endpoints []HorizonEndpoints) (*Client, error) {
 
 	if config.Cache != nil {
 		c.cache = cache.New(config.Cache.TTL, 10*time.Minute)
-		c.keyGen = config.Cache.KeyGen
+		c.keyGen = cacheKeyGen(config.Cache)
 	}
 
 	return c, nil
 }
 
+func (c *Client) settings() clientSettings {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return clientSettings{
+		cache:     c.cache,
+		publicKey: c.publicKey,
+		keyGen:    c.keyGen,
+		endpoints: c.endpoints,
+	}
+}
+
+// reconfigure swaps the endpoints, public key and cache settings. Cached evaluations
+// survive unless resetCache is set, keeping their original expiry when the TTL changes.
+func (c *Client) reconfigure(config Config, endpoints []HorizonEndpoints, resetCache bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous := c.config
+	c.config = config
+	c.publicKey = config.PublicKey
+	c.endpoints = endpoints
+
+	switch {
+	case config.Cache == nil:
+		c.cache = nil
+		c.keyGen = nil
+		return
+	case c.cache == nil || resetCache:
+		c.cache = cache.New(config.Cache.TTL, 10*time.Minute)
+	case previous.Cache == nil || previous.Cache.TTL != config.Cache.TTL:
+		c.cache = cache.NewFrom(config.Cache.TTL, 10*time.Minute, c.cache.Items())
+	}
+	c.keyGen = cacheKeyGen(config.Cache)
+}
+
+// DefaultCacheKey is the cache key of contexts when CacheConfig.KeyGen is not set:
+// the JSON encoding of the whole context, so only identical contexts share an entry.
+func DefaultCacheKey(ctx EvaluationContext) string {
+	data, err := json.Marshal(ctx)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func cacheKeyGen(config *CacheConfig) func(ctx EvaluationContext) string {
+	if config.KeyGen != nil {
+		return config.KeyGen
+	}
+	return DefaultCacheKey
+}
+
 func (c *Client) Evaluate(ctx EvaluationContext) (*Response, error) {
-	if c.cache != nil && c.keyGen != nil {
-		key := c.keyGen(ctx)
-		if cached, found := c.cache.Get(key); found {
-			return cached.(*Response), nil
+	s := c.settings()
+	if s.cache != nil && s.keyGen != nil {
+		key := s.keyGen(ctx)
+		if cached, found := s.cache.Get(key); found {
+			resp := *cached.(*Response)
+			resp.Cached = true
+			return &resp, nil
 		}
 	}
 	var lastErr error
-	for _, endpoint := range c.endpoints {
-		resp, err := c.fetchEvaluation(endpoint.Evaluate, ctx)
+	for _, endpoint := range s.endpoints {
+		resp, err := c.fetchEvaluation(endpoint.Evaluate, s.publicKey, ctx)
 		if err != nil {
 			lastErr = err
 			continue
 		}
-		if c.cache != nil && c.keyGen != nil {
-			key := c.keyGen(ctx)
-			c.cache.Set(key, resp, cache.DefaultExpiration)
+		if s.cache != nil && s.keyGen != nil {
+			key := s.keyGen(ctx)
+			s.cache.Set(key, resp, cache.DefaultExpiration)
 		}
 		return resp, nil
 	}
 	return nil, fmt.Errorf("all evaluation attempts failed: %v", lastErr)
 }
 
-func (c *Client) fetchEvaluation(evaluateURL string, ctx EvaluationContext) (*Response, error) {
+func (c *Client) fetchEvaluation(evaluateURL, publicKey string, ctx EvaluationContext) (*Response, error) {
 	payload, err := json.Marshal(ctx)
 	if err != nil {
 		return nil, err
@@ -78,7 +152,7 @@ func (c *Client) fetchEvaluation(evaluateURL string, ctx EvaluationContext) (*Re
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", c.publicKey)
+	req.Header.Set("x-api-key", publicKey)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -90,8 +164,12 @@ func (c *Client) fetchEvaluation(evaluateURL string, ctx EvaluationContext) (*Re
 		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
 	}
 
+	// Decode numbers as json.Number so integer flags keep their exact value.
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+
 	var result Response
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := decoder.Decode(&result); err != nil {
 		return nil, err
 	}
 
@@ -99,9 +177,10 @@ func (c *Client) fetchEvaluation(evaluateURL string, ctx EvaluationContext) (*Re
 }
 
 func (c *Client) SendTelemetry(payload TelemetryPayload) error {
+	s := c.settings()
 	var lastErr error
-	for _, endpoint := range c.endpoints {
-		err := c.postTelemetry(endpoint.Telemetry, payload)
+	for _, endpoint := range s.endpoints {
+		err := c.postTelemetry(endpoint.Telemetry, s.publicKey, payload)
 		if err != nil {
 			lastErr = err
 			continue
@@ -111,7 +190,40 @@ func (c *Client) SendTelemetry(payload TelemetryPayload) error {
 	return fmt.Errorf("all telemetry attempts failed: %v", lastErr)
 }
 
-func (c *Client) postTelemetry(telemetryURL string, payload TelemetryPayload) error {
+// SendTrackingEvent posts a custom tracking event through the telemetry endpoints.
+func (c *Client) SendTrackingEvent(payload TrackingPayload) error {
+	s := c.settings()
+	var lastErr error
+	for _, endpoint := range s.endpoints {
+		err := c.postTelemetry(endpoint.Telemetry, s.publicKey, payload)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("all tracking attempts failed: %v", lastErr)
+}
+
+// SendExposure posts an experiment exposure through the telemetry endpoints.
+func (c *Client) SendExposure(event ExposureEvent) error {
+	payload := ExposurePayload{Context: event.Context}
+	payload.Data.Exposure = event
+
+	s := c.settings()
+	var lastErr error
+	for _, endpoint := range s.endpoints {
+		err := c.postTelemetry(endpoint.Telemetry, s.publicKey, payload)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("all exposure attempts failed: %v", lastErr)
+}
+
+func (c *Client) postTelemetry(telemetryURL, publicKey string, payload interface{}) error {
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return err
@@ -123,7 +235,7 @@ func (c *Client) postTelemetry(telemetryURL string, payload TelemetryPayload) er
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", c.publicKey)
+	req.Header.Set("x-api-key", publicKey)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -165,11 +165,14 @@ func TestClientEvaluateWithCache(t *testing.T) {
 	assert.NotNil(t, resp1)
 	assert.Equal(t, 1, callCount)
 
+	assert.False(t, resp1.Cached)
+
 	// Second call should use cache
 	resp2, err := client.Evaluate(ctx)
 	assert.NoError(t, err)
 	assert.NotNil(t, resp2)
 	assert.Equal(t, 1, callCount) // Call count should not increase
+	assert.True(t, resp2.Cached)
 }
 
 func TestClientSendTelemetry(t *testing.T) {
@@ -215,6 +218,34 @@ func TestClientSendTelemetry(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestClientSendTrackingEvent(t *testing.T) {
+	var received TrackingPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/toggle/telemetry", r.URL.Path)
+		assert.Equal(t, "test-key", r.Header.Get("x-api-key"))
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := newClient(Config{PublicKey: "test-key"}, newEndpoints([]string{server.URL}))
+	assert.NoError(t, err)
+
+	payload := TrackingPayload{
+		Context: EvaluationContext{
+			TargetingKey: "test-user",
+			Application:  "test-app",
+			Environment:  "test-env",
+		},
+	}
+	payload.Data.Event = TrackingEvent{Name: "checkout", Value: 10}
+
+	err = client.SendTrackingEvent(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, "checkout", received.Data.Event.Name)
+	assert.Equal(t, 10.0, received.Data.Event.Value)
+}
+
 func TestEvaluate(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
@@ -1,6 +1,7 @@
 package toggle
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 )
@@ -30,6 +31,7 @@ var (
 func newEndpoints(urls []string) []HorizonEndpoints {
 	endpoints := make([]HorizonEndpoints, len(urls))
 	for i, url := range urls {
+		url = normalizeHorizonURL(url)
 		endpoints[i] = HorizonEndpoints{
 			Evaluate:  url + "/toggle/evaluate",
 			Telemetry: url + "/toggle/telemetry",
@@ -59,21 +61,68 @@ func validateEnvironmentFormat(environment string) error {
 	return nil
 }
 
+// validateConfig runs the checks every provider config must pass and returns the
+// first problem found. ValidateConfig runs the same checks plus stricter ones.
 func validateConfig(config Config) error {
+	v := &configValidator{}
+	checkConfig(v, config)
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs[0].Err
+}
+
+// checkConfig reports the problems shared by validateConfig and ValidateConfig to v.
+func checkConfig(v *configValidator, config Config) {
 	if config.Application == "" {
-		return ErrMissingApplication
+		v.add("Application", ErrMissingApplication)
 	}
 	if config.Environment == "" {
-		return ErrMissingEnvironment
+		v.add("Environment", ErrMissingEnvironment)
+	} else if err := validateEnvironmentFormat(config.Environment); err != nil {
+		v.add("Environment", err)
+	}
+
+	if config.PublicKey == "" {
+		v.add("PublicKey", ErrMissingPublicKey)
+	} else if config.StrictPublicKey {
+		if _, err := ParsePublicKey(config.PublicKey); err != nil {
+			v.add("PublicKey", err)
+		}
 	}
 
-	// Validate environment format
-	if err := validateEnvironmentFormat(config.Environment); err != nil {
-		return err
+	if config.Cache != nil {
+		if err := validateCacheTTL(config.Cache.TTL); err != nil {
+			v.add("Cache.TTL", err)
+		}
 	}
 
-	if config.PublicKey == "" {
-		return ErrMissingPublicKey
+	if config.Exposures != nil && config.Exposures.DedupWindow < 0 {
+		v.add("Exposures.DedupWindow", fmt.Errorf("%w: %s", ErrInvalidDedupWindow, config.Exposures.DedupWindow))
 	}
-	return nil
+
+	if schema := config.ContextSchema; schema != nil && schema.WarnOnly && schema.Warn == nil {
+		v.add("ContextSchema.Warn", ErrMissingSchemaWarn)
+	}
+
+	for i, o := range config.Overrides {
+		if err := validateOverride(o); err != nil {
+			v.add(fmt.Sprintf("Overrides[%d]", i), err)
+		}
+	}
+	if config.AllowRequestOverrides && !requestOverridesAllowed(config) {
+		v.add("AllowRequestOverrides", fmt.Errorf("%w: %s is not listed in RequestOverrideEnvironments", ErrRequestOverridesNotAllowed, config.Environment))
+	}
+}
+
+// requestOverridesAllowed reports whether config.Environment is listed in
+// config.RequestOverrideEnvironments. Environments are never judged by their name,
+// since names such as "prd" or "live" and project environment IDs cannot be told apart.
+func requestOverridesAllowed(config Config) bool {
+	for _, environment := range config.RequestOverrideEnvironments {
+		if environment == config.Environment {
+			return true
+		}
+	}
+	return false
 }
@@ -1,8 +1,10 @@
 package toggle
 
 import (
-	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewEndpoints(t *testing.T) {
@@ -158,12 +160,46 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: ErrMissingPublicKey,
 		},
+		{
+			name: "zero cache TTL",
+			config: Config{
+				Application: "test-app",
+				Environment: "production",
+				PublicKey:   "test-key",
+				Cache:       &CacheConfig{},
+			},
+			wantErr: ErrInvalidCacheTTL,
+		},
+		{
+			name: "negative cache TTL",
+			config: Config{
+				Application: "test-app",
+				Environment: "production",
+				PublicKey:   "test-key",
+				Cache:       &CacheConfig{TTL: -time.Minute},
+			},
+			wantErr: ErrInvalidCacheTTL,
+		},
+		{
+			name: "negative dedup window",
+			config: Config{
+				Application: "test-app",
+				Environment: "production",
+				PublicKey:   "test-key",
+				Exposures:   &ExposureConfig{DedupWindow: -time.Minute},
+			},
+			wantErr: ErrInvalidDedupWindow,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			err := validateConfig(tt.config)
-			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
 		})
 	}
 }
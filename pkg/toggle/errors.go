@@ -3,11 +3,35 @@ package toggle
 import "errors"
 
 var (
-	ErrMissingApplication       = errors.New("application is required")
-	ErrMissingEnvironment       = errors.New("environment is required")
-	ErrMissingPublicKey         = errors.New("public key is required")
-	ErrMissingTargetKey         = errors.New("targeting key is required")
-	ErrInvalidFlagType          = errors.New("invalid flag type")
-	ErrFlagNotFound             = errors.New("flag not found")
-	ErrInvalidEnvironmentFormat = errors.New("invalid environment format. Must be either a project environment ID (starting with \"pevr_\") or a valid alternateId (1-25 characters, lowercase letters, numbers, hyphens, and underscores, not containing the word \"environments\")")
+	ErrMissingApplication         = errors.New("application is required")
+	ErrMissingEnvironment         = errors.New("environment is required")
+	ErrMissingPublicKey           = errors.New("public key is required")
+	ErrMissingTargetKey           = errors.New("targeting key is required")
+	ErrInvalidFlagType            = errors.New("invalid flag type")
+	ErrFlagNotFound               = errors.New("flag not found")
+	ErrNumberOverflow             = errors.New("number out of range")
+	ErrNonIntegralNumber          = errors.New("number is not an integer")
+	ErrInvalidAttributeMapping    = errors.New("invalid attribute mapping")
+	ErrUnknownContextKey          = errors.New("unknown evaluation context key")
+	ErrMissingContextField        = errors.New("required evaluation context field is empty")
+	ErrInvalidContextValue        = errors.New("invalid evaluation context value")
+	ErrInvalidContextStruct       = errors.New("evaluation context source must be a struct")
+	ErrInvalidContext             = errors.New("invalid evaluation context")
+	ErrUnsupportedConfigFormat    = errors.New("unsupported config file format, expected .yaml, .yml, .json or .toml")
+	ErrInvalidPublicKey           = errors.New("invalid public key")
+	ErrInvalidHorizonURL          = errors.New("invalid horizon url")
+	ErrDuplicateHorizonURL        = errors.New("duplicate horizon url")
+	ErrInvalidWebhookURL          = errors.New("invalid webhook url")
+	ErrInvalidCacheTTL            = errors.New("invalid cache ttl")
+	ErrInvalidDedupWindow         = errors.New("invalid exposure dedup window")
+	ErrNoRoutes                   = errors.New("routing provider needs at least one route")
+	ErrUnknownRoute               = errors.New("unknown route")
+	ErrInvalidLocalFlag           = errors.New("invalid local flag")
+	ErrEmptyChain                 = errors.New("chain requires at least one provider")
+	ErrMissingShadowProvider      = errors.New("shadow evaluation requires a primary and a shadow provider")
+	ErrInvalidSampleRate          = errors.New("sample rate must be between 0 and 1")
+	ErrMissingSchemaWarn          = errors.New("context schema in WarnOnly mode requires Warn")
+	ErrInvalidOverride            = errors.New("invalid override")
+	ErrRequestOverridesNotAllowed = errors.New("request overrides are not allowed in this environment")
+	ErrInvalidEnvironmentFormat   = errors.New("invalid environment format. Must be either a project environment ID (starting with \"pevr_\") or a valid alternateId (1-25 characters, lowercase letters, numbers, hyphens, and underscores, not containing the word \"environments\")")
 )
@@ -0,0 +1,121 @@
+package toggle
+
+import (
+	"context"
+	"errors"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// coercer converts a Horizon toggle into the Go type requested by the caller.
+// It returns an error when the toggle's declared type or value does not match.
+type coercer[T any] func(toggle Evaluation) (T, error)
+
+// resolve runs the shared resolution pipeline for every flag type: it applies any
+// override, builds the Hyphen context, evaluates it through the client, looks the
+// flag up and coerces its value. On any failure defaultValue is returned together
+// with the error detail.
+func resolve[T any](ctx context.Context, p *Provider, flag string, defaultValue T, evalCtx openfeature.FlattenedContext, coerce coercer[T]) (T, openfeature.ProviderResolutionDetail) {
+	if toggle, scope, ok := p.override(ctx, flag, evalCtx); ok {
+		value, err := coerce(toggle)
+		if err != nil {
+			return defaultValue, errorDetail(openfeature.NewTypeMismatchResolutionError(err.Error()))
+		}
+		return value, overrideDetail(scope)
+	}
+
+	hyphenCtx, err := p.buildContext(evalCtx)
+	if err != nil {
+		return defaultValue, contextErrorDetail(err)
+	}
+
+	eval, err := p.client.Evaluate(hyphenCtx)
+	if err != nil {
+		return defaultValue, errorDetail(openfeature.NewGeneralResolutionError(err.Error()))
+	}
+
+	return resolveToggle(eval, flag, defaultValue, coerce)
+}
+
+// resolveToggle looks flag up in an already fetched Horizon response and coerces its value.
+func resolveToggle[T any](eval *Response, flag string, defaultValue T, coerce coercer[T]) (T, openfeature.ProviderResolutionDetail) {
+	toggle, resErr, ok := lookupToggle(eval, flag)
+	if !ok {
+		return defaultValue, errorDetail(resErr)
+	}
+
+	value, err := coerce(toggle)
+	if err != nil {
+		return defaultValue, errorDetail(openfeature.NewTypeMismatchResolutionError(err.Error()))
+	}
+
+	return value, resolutionDetail(eval, toggle)
+}
+
+// contextErrorDetail reports a failure to build the Hyphen context. Schema
+// violations resolve with INVALID_CONTEXT.
+func contextErrorDetail(err error) openfeature.ProviderResolutionDetail {
+	if errors.Is(err, ErrInvalidContext) {
+		return errorDetail(openfeature.NewInvalidContextResolutionError(err.Error()))
+	}
+	return errorDetail(openfeature.NewParseErrorResolutionError(err.Error()))
+}
+
+func errorDetail(resErr openfeature.ResolutionError) openfeature.ProviderResolutionDetail {
+	return openfeature.ProviderResolutionDetail{
+		Reason:          openfeature.ErrorReason,
+		ResolutionError: resErr,
+	}
+}
+
+func coerceBool(toggle Evaluation) (bool, error) {
+	if toggle.Type != "boolean" {
+		return false, ErrInvalidFlagType
+	}
+	value, ok := toggle.Value.(bool)
+	if !ok {
+		return false, ErrInvalidFlagType
+	}
+	return value, nil
+}
+
+func coerceString(toggle Evaluation) (string, error) {
+	if toggle.Type != "string" {
+		return "", ErrInvalidFlagType
+	}
+	value, ok := toggle.Value.(string)
+	if !ok {
+		return "", ErrInvalidFlagType
+	}
+	return value, nil
+}
+
+func coerceFloat(toggle Evaluation) (float64, error) {
+	if toggle.Type != "number" {
+		return 0, ErrInvalidFlagType
+	}
+	return toFloat64(toggle.Value)
+}
+
+// coerceInt returns an int coercer that rounds non-integral values according to policy.
+func coerceInt(policy RoundingPolicy) coercer[int64] {
+	return func(toggle Evaluation) (int64, error) {
+		if toggle.Type != "number" {
+			return 0, ErrInvalidFlagType
+		}
+		return toInt64(toggle.Value, policy)
+	}
+}
+
+// coerceObject accepts JSON objects and arrays, the two shapes Horizon uses for object flags.
+func coerceObject(toggle Evaluation) (interface{}, error) {
+	if toggle.Type != "object" {
+		return nil, ErrInvalidFlagType
+	}
+	switch toggle.Value.(type) {
+	case map[string]interface{}, []interface{}:
+		return normalizeNumbers(toggle.Value), nil
+	default:
+		return nil, ErrInvalidFlagType
+	}
+}
@@ -0,0 +1,247 @@
+package toggle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+// evaluateAs dispatches to the typed evaluation method for flagType so the same
+// table can exercise every resolver.
+func evaluateAs(p *Provider, flagType openfeature.Type, flag string, evalCtx openfeature.FlattenedContext) (interface{}, openfeature.ProviderResolutionDetail) {
+	ctx := context.Background()
+	switch flagType {
+	case openfeature.Boolean:
+		res := p.BooleanEvaluation(ctx, flag, false, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	case openfeature.String:
+		res := p.StringEvaluation(ctx, flag, "default", evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	case openfeature.Float:
+		res := p.FloatEvaluation(ctx, flag, 1.5, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	case openfeature.Int:
+		res := p.IntEvaluation(ctx, flag, 42, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	default:
+		res := p.ObjectEvaluation(ctx, flag, "default", evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	}
+}
+
+var evaluationDefaults = map[openfeature.Type]interface{}{
+	openfeature.Boolean: false,
+	openfeature.String:  "default",
+	openfeature.Float:   1.5,
+	openfeature.Int:     int64(42),
+	openfeature.Object:  "default",
+}
+
+type resolveCase struct {
+	name       string
+	flagType   openfeature.Type
+	evalCtx    openfeature.FlattenedContext
+	toggle     *Evaluation
+	clientErr  error
+	wantValue  interface{}
+	wantReason openfeature.Reason
+	wantCode   openfeature.ErrorCode
+}
+
+func TestResolveMatrix(t *testing.T) {
+	validCtx := openfeature.FlattenedContext{"targetingKey": "user-123"}
+	allTypes := []openfeature.Type{openfeature.Boolean, openfeature.String, openfeature.Float, openfeature.Int, openfeature.Object}
+
+	tests := []resolveCase{
+		{
+			name:       "boolean",
+			flagType:   openfeature.Boolean,
+			toggle:     &Evaluation{Type: "boolean", Value: true, Reason: "targeting_match"},
+			wantValue:  true,
+			wantReason: openfeature.TargetingMatchReason,
+		},
+		{
+			name:       "string",
+			flagType:   openfeature.String,
+			toggle:     &Evaluation{Type: "string", Value: "blue", Reason: "split"},
+			wantValue:  "blue",
+			wantReason: openfeature.SplitReason,
+		},
+		{
+			name:       "float",
+			flagType:   openfeature.Float,
+			toggle:     &Evaluation{Type: "number", Value: 0.25, Reason: "static"},
+			wantValue:  0.25,
+			wantReason: openfeature.StaticReason,
+		},
+		{
+			name:       "int",
+			flagType:   openfeature.Int,
+			toggle:     &Evaluation{Type: "number", Value: float64(7), Reason: "default"},
+			wantValue:  int64(7),
+			wantReason: openfeature.DefaultReason,
+		},
+		{
+			name:       "object map",
+			flagType:   openfeature.Object,
+			toggle:     &Evaluation{Type: "object", Value: map[string]interface{}{"a": 1.0}, Reason: "disabled"},
+			wantValue:  map[string]interface{}{"a": 1.0},
+			wantReason: openfeature.DisabledReason,
+		},
+		{
+			name:       "object array",
+			flagType:   openfeature.Object,
+			toggle:     &Evaluation{Type: "object", Value: []interface{}{"a", "b"}},
+			wantValue:  []interface{}{"a", "b"},
+			wantReason: openfeature.TargetingMatchReason,
+		},
+		{
+			name:       "int from json number beyond 2^53",
+			flagType:   openfeature.Int,
+			toggle:     &Evaluation{Type: "number", Value: json.Number("9007199254740993")},
+			wantValue:  int64(9007199254740993),
+			wantReason: openfeature.TargetingMatchReason,
+		},
+		{
+			name:       "float from json number",
+			flagType:   openfeature.Float,
+			toggle:     &Evaluation{Type: "number", Value: json.Number("0.1")},
+			wantValue:  0.1,
+			wantReason: openfeature.TargetingMatchReason,
+		},
+		{
+			name:       "object with json numbers",
+			flagType:   openfeature.Object,
+			toggle:     &Evaluation{Type: "object", Value: map[string]interface{}{"limit": json.Number("10")}},
+			wantValue:  map[string]interface{}{"limit": 10.0},
+			wantReason: openfeature.TargetingMatchReason,
+		},
+		{
+			name:     "int rejects fraction",
+			flagType: openfeature.Int,
+			toggle:   &Evaluation{Type: "number", Value: json.Number("3.7")},
+			wantCode: openfeature.TypeMismatchCode,
+		},
+		{
+			name:     "int rejects overflow",
+			flagType: openfeature.Int,
+			toggle:   &Evaluation{Type: "number", Value: json.Number("1e20")},
+			wantCode: openfeature.TypeMismatchCode,
+		},
+		{
+			name:     "boolean declared with wrong value",
+			flagType: openfeature.Boolean,
+			toggle:   &Evaluation{Type: "boolean", Value: "true"},
+			wantCode: openfeature.TypeMismatchCode,
+		},
+		{
+			name:     "string declared with wrong value",
+			flagType: openfeature.String,
+			toggle:   &Evaluation{Type: "string", Value: 12.0},
+			wantCode: openfeature.TypeMismatchCode,
+		},
+		{
+			name:     "float declared with wrong value",
+			flagType: openfeature.Float,
+			toggle:   &Evaluation{Type: "number", Value: "1.5"},
+			wantCode: openfeature.TypeMismatchCode,
+		},
+		{
+			name:     "float rejects non-json int",
+			flagType: openfeature.Float,
+			toggle:   &Evaluation{Type: "number", Value: 3},
+			wantCode: openfeature.TypeMismatchCode,
+		},
+		{
+			name:     "int declared with wrong value",
+			flagType: openfeature.Int,
+			toggle:   &Evaluation{Type: "number", Value: true},
+			wantCode: openfeature.TypeMismatchCode,
+		},
+		{
+			name:     "object declared with scalar value",
+			flagType: openfeature.Object,
+			toggle:   &Evaluation{Type: "object", Value: "not an object"},
+			wantCode: openfeature.TypeMismatchCode,
+		},
+	}
+
+	// Error paths that behave the same for every flag type.
+	for _, flagType := range allTypes {
+		name := flagType.String()
+		tests = append(tests,
+			resolveCase{name: name + " missing targeting key", flagType: flagType, evalCtx: openfeature.FlattenedContext{}, wantCode: openfeature.ParseErrorCode},
+			resolveCase{name: name + " client error", flagType: flagType, clientErr: errors.New("boom"), wantCode: openfeature.GeneralCode},
+			resolveCase{name: name + " flag not found", flagType: flagType, wantCode: openfeature.FlagNotFoundCode},
+			resolveCase{name: name + " server error", flagType: flagType, toggle: &Evaluation{Type: typeToString[flagType], Error: "flag not found"}, wantCode: openfeature.FlagNotFoundCode},
+			resolveCase{name: name + " declared type mismatch", flagType: flagType, toggle: &Evaluation{Type: "unknown", Value: true}, wantCode: openfeature.TypeMismatchCode},
+		)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Provider{
+				client: &MockClient{
+					EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+						if tt.clientErr != nil {
+							return nil, tt.clientErr
+						}
+						resp := &Response{Toggles: map[string]Evaluation{}}
+						if tt.toggle != nil {
+							resp.Toggles["test-flag"] = *tt.toggle
+						}
+						return resp, nil
+					},
+				},
+				config: Config{
+					Application: "test-app",
+					Environment: "test-env",
+				},
+			}
+
+			evalCtx := tt.evalCtx
+			if evalCtx == nil {
+				evalCtx = validCtx
+			}
+
+			value, detail := evaluateAs(p, tt.flagType, "test-flag", evalCtx)
+			resolution := detail.ResolutionDetail()
+
+			if tt.wantCode != "" {
+				assert.Equal(t, evaluationDefaults[tt.flagType], value)
+				assert.Equal(t, openfeature.ErrorReason, detail.Reason)
+				assert.Equal(t, tt.wantCode, resolution.ErrorCode)
+				return
+			}
+
+			assert.Equal(t, tt.wantValue, value)
+			assert.Equal(t, tt.wantReason, detail.Reason)
+			assert.Empty(t, resolution.ErrorCode)
+		})
+	}
+}
+
+func TestIntEvaluationRoundingPolicy(t *testing.T) {
+	p := &Provider{
+		client: &MockClient{
+			EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+				return &Response{Toggles: map[string]Evaluation{
+					"quota": {Type: "number", Value: json.Number("3.7")},
+				}}, nil
+			},
+		},
+		config: Config{
+			Application: "test-app",
+			Environment: "test-env",
+			IntRounding: RoundingNearest,
+		},
+	}
+
+	res := p.IntEvaluation(context.Background(), "quota", 0, openfeature.FlattenedContext{"targetingKey": "user-123"})
+	assert.Equal(t, int64(4), res.Value)
+	assert.NoError(t, res.Error())
+}
@@ -0,0 +1,139 @@
+package toggle
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/patrickmn/go-cache"
+)
+
+// DefaultExposureDedupWindow is how long an exposure is remembered when
+// ExposureConfig.DedupWindow is not set.
+const DefaultExposureDedupWindow = 24 * time.Hour
+
+// ExposureConfig enables experiment exposure events.
+type ExposureConfig struct {
+	// Sinks receive every exposure in addition to Horizon.
+	Sinks []ExposureSink
+	// DedupWindow is how long a user's exposure to an experiment variant is
+	// remembered before it is reported again.
+	DedupWindow time.Duration
+}
+
+// ExposureSink receives experiment exposure events, for example to export them to a data warehouse.
+type ExposureSink interface {
+	SendExposure(event ExposureEvent) error
+}
+
+// ExposureEvent records that a user was assigned a variant of an experiment.
+// Unlike the usage TelemetryPayload it is only emitted for evaluations that
+// resolved a variant, and only once per user, experiment and variant within the dedup window.
+type ExposureEvent struct {
+	FlagKey      string            `json:"flagKey"`
+	Variant      string            `json:"variant"`
+	ExperimentID string            `json:"experimentId,omitempty"`
+	BucketingKey string            `json:"bucketingKey"`
+	Reason       string            `json:"reason,omitempty"`
+	Timestamp    time.Time         `json:"timestamp"`
+	Context      EvaluationContext `json:"context"`
+}
+
+// ExposurePayload is the body posted to Horizon's telemetry endpoints for an exposure.
+type ExposurePayload struct {
+	Context EvaluationContext `json:"context"`
+	Data    struct {
+		Exposure ExposureEvent `json:"exposure"`
+	} `json:"data"`
+}
+
+type exposureTracker struct {
+	seen  *cache.Cache
+	sinks []ExposureSink
+}
+
+func newExposureTracker(config ExposureConfig, sinks ...ExposureSink) *exposureTracker {
+	window := config.DedupWindow
+	if window <= 0 {
+		window = DefaultExposureDedupWindow
+	}
+	return &exposureTracker{
+		seen:  cache.New(window, window),
+		sinks: append(sinks, config.Sinks...),
+	}
+}
+
+// record sends event to extra and to every configured sink unless the same
+// exposure was already delivered within the dedup window. Delivery continues past
+// failing sinks, and an exposure no sink accepted is not remembered, so the next
+// evaluation retries it.
+func (t *exposureTracker) record(event ExposureEvent, extra ...ExposureSink) error {
+	key := event.BucketingKey + "\x00" + event.FlagKey + "\x00" + event.ExperimentID + "\x00" + event.Variant
+	// Add claims the key, so concurrent evaluations deliver an exposure only once.
+	if err := t.seen.Add(key, struct{}{}, cache.DefaultExpiration); err != nil {
+		return nil
+	}
+
+	var errs []error
+	delivered := false
+	for _, sink := range append(extra, t.sinks...) {
+		if err := sink.SendExposure(event); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delivered = true
+	}
+	if !delivered {
+		t.seen.Delete(key)
+	}
+	return errors.Join(errs...)
+}
+
+// exposureFromEvaluation builds the exposure for a successful evaluation that
+// resolved a variant. It returns false for evaluations that are not experiment assignments.
+func (p *Provider) exposureFromEvaluation(evalCtx openfeature.EvaluationContext, details openfeature.InterfaceEvaluationDetails) (ExposureEvent, bool) {
+	if details.Variant == "" || details.ErrorCode != "" {
+		return ExposureEvent{}, false
+	}
+
+	hyphenCtx, err := p.buildContext(flattenEvaluationContext(evalCtx))
+	if err != nil {
+		return ExposureEvent{}, false
+	}
+
+	experimentID, _ := details.FlagMetadata.GetString(MetadataKeyRuleID)
+
+	return ExposureEvent{
+		FlagKey:      details.FlagKey,
+		Variant:      details.Variant,
+		ExperimentID: experimentID,
+		BucketingKey: hyphenCtx.TargetingKey,
+		Reason:       string(details.Reason),
+		Timestamp:    time.Now().UTC(),
+		Context:      hyphenCtx,
+	}, true
+}
+
+// recordExposure reports the exposure for details, if any. Failures are logged
+// rather than returned so they never fail the evaluation itself.
+func (p *Provider) recordExposure(ctx context.Context, evalCtx openfeature.EvaluationContext, details openfeature.InterfaceEvaluationDetails) {
+	p.mu.RLock()
+	exposures := p.exposures
+	p.mu.RUnlock()
+	if exposures == nil {
+		return
+	}
+	event, ok := p.exposureFromEvaluation(evalCtx, details)
+	if !ok {
+		return
+	}
+	// Exposures reach Horizon only when usage telemetry is enabled.
+	var horizon []ExposureSink
+	if sink, ok := p.client.(ExposureSink); ok && p.usageEnabled() {
+		horizon = append(horizon, sink)
+	}
+	if err := exposures.record(event, horizon...); err != nil {
+		logError(ctx, "Error recording exposure:", err)
+	}
+}
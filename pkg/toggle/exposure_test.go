@@ -0,0 +1,174 @@
+package toggle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingExposureSink struct {
+	events []ExposureEvent
+	err    error
+}
+
+func (s *recordingExposureSink) SendExposure(event ExposureEvent) error {
+	s.events = append(s.events, event)
+	return s.err
+}
+
+func TestExposureTracker_Dedup(t *testing.T) {
+	sink := &recordingExposureSink{}
+	tracker := newExposureTracker(ExposureConfig{Sinks: []ExposureSink{sink}, DedupWindow: time.Minute})
+
+	event := ExposureEvent{FlagKey: "checkout", Variant: "treatment", ExperimentID: "exp-1", BucketingKey: "user-1"}
+	assert.NoError(t, tracker.record(event))
+	assert.NoError(t, tracker.record(event))
+	assert.Len(t, sink.events, 1)
+
+	other := event
+	other.BucketingKey = "user-2"
+	assert.NoError(t, tracker.record(other))
+
+	reassigned := event
+	reassigned.Variant = "control"
+	assert.NoError(t, tracker.record(reassigned))
+
+	assert.Len(t, sink.events, 3)
+}
+
+func TestExposureTracker_SinkErrors(t *testing.T) {
+	failing := &recordingExposureSink{err: errors.New("warehouse down")}
+	working := &recordingExposureSink{}
+	tracker := newExposureTracker(ExposureConfig{}, failing, working)
+
+	err := tracker.record(ExposureEvent{FlagKey: "checkout", Variant: "treatment", BucketingKey: "user-1"})
+	assert.EqualError(t, err, "warehouse down")
+	assert.Len(t, working.events, 1)
+}
+
+func TestExposureTracker_RetriesUndelivered(t *testing.T) {
+	sink := &recordingExposureSink{err: errors.New("warehouse down")}
+	tracker := newExposureTracker(ExposureConfig{Sinks: []ExposureSink{sink}})
+	event := ExposureEvent{FlagKey: "checkout", Variant: "treatment", BucketingKey: "user-1"}
+
+	// Exposures no sink accepted are not remembered.
+	assert.Error(t, tracker.record(event))
+	assert.Error(t, tracker.record(event))
+	assert.Len(t, sink.events, 2)
+
+	sink.err = nil
+	assert.NoError(t, tracker.record(event))
+	assert.NoError(t, tracker.record(event))
+	assert.Len(t, sink.events, 3)
+
+	// One accepting sink is enough to remember the exposure.
+	other := event
+	other.BucketingKey = "user-2"
+	failing := &recordingExposureSink{err: errors.New("horizon down")}
+	assert.Error(t, tracker.record(other, failing))
+	assert.NoError(t, tracker.record(other, failing))
+	assert.Len(t, failing.events, 1)
+	assert.Len(t, sink.events, 4)
+}
+
+// exposureClient is a MockClient that also accepts exposures, as Client does.
+type exposureClient struct {
+	MockClient
+	recordingExposureSink
+}
+
+func TestProvider_ExposuresFollowEnableUsage(t *testing.T) {
+	tests := []struct {
+		name        string
+		enableUsage bool
+		wantHorizon int
+	}{
+		{name: "usage enabled", enableUsage: true, wantHorizon: 1},
+		{name: "usage disabled", enableUsage: false, wantHorizon: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordingExposureSink{}
+			client := &exposureClient{MockClient: MockClient{
+				EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+					return &Response{Toggles: map[string]Evaluation{
+						"checkout": {Type: "boolean", Value: true, Reason: "split", Variant: "treatment", RuleID: "exp-1"},
+					}}, nil
+				},
+			}}
+			enableUsage := tt.enableUsage
+			p, err := NewProviderWithClient(Config{
+				PublicKey:   testPublicKey,
+				Application: "test-app",
+				Environment: "test-env",
+				EnableUsage: &enableUsage,
+				Exposures:   &ExposureConfig{Sinks: []ExposureSink{sink}},
+			}, client)
+			assert.NoError(t, err)
+
+			details := openfeature.InterfaceEvaluationDetails{
+				Value: true,
+				EvaluationDetails: openfeature.EvaluationDetails{
+					FlagKey:  "checkout",
+					FlagType: openfeature.Boolean,
+					ResolutionDetail: openfeature.ResolutionDetail{
+						Variant: "treatment",
+						Reason:  openfeature.SplitReason,
+					},
+				},
+			}
+			p.recordExposure(context.Background(), openfeature.NewEvaluationContext("user-123", nil), details)
+
+			assert.Len(t, client.events, tt.wantHorizon)
+			assert.Len(t, sink.events, 1)
+		})
+	}
+}
+
+func TestProviderHook_RecordsExposures(t *testing.T) {
+	sink := &recordingExposureSink{}
+	disableUsage := false
+	p := &Provider{
+		client: &MockClient{
+			EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+				return &Response{Toggles: map[string]Evaluation{
+					"checkout": {Type: "boolean", Value: true, Reason: "split", Variant: "treatment", RuleID: "exp-1"},
+					"plain":    {Type: "boolean", Value: true},
+				}}, nil
+			},
+		},
+		config: Config{
+			Application: "test-app",
+			Environment: "test-env",
+			EnableUsage: &disableUsage,
+		},
+		exposures: newExposureTracker(ExposureConfig{Sinks: []ExposureSink{sink}}),
+	}
+	p.hooks = []openfeature.Hook{NewProviderHook(p)}
+
+	assert.NoError(t, openfeature.SetNamedProviderAndWait("exposure-test", p))
+	client := openfeature.NewClient("exposure-test")
+	evalCtx := openfeature.NewEvaluationContext("user-123", nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.BooleanValue(context.Background(), "checkout", false, evalCtx)
+		assert.NoError(t, err)
+	}
+	_, err := client.BooleanValue(context.Background(), "plain", false, evalCtx)
+	assert.NoError(t, err)
+
+	assert.Len(t, sink.events, 1)
+	event := sink.events[0]
+	assert.Equal(t, "checkout", event.FlagKey)
+	assert.Equal(t, "treatment", event.Variant)
+	assert.Equal(t, "exp-1", event.ExperimentID)
+	assert.Equal(t, "user-123", event.BucketingKey)
+	assert.Equal(t, string(openfeature.SplitReason), event.Reason)
+	assert.Equal(t, "test-app", event.Context.Application)
+	assert.False(t, event.Timestamp.IsZero())
+}
@@ -25,16 +25,17 @@ func (h *ProviderHook) Before(ctx context.Context, hookContext openfeature.HookC
 		attributes[k] = v
 	}
 
-	attributes["application"] = h.provider.config.Application
-	attributes["environment"] = h.provider.config.Environment
+	config := h.provider.settings()
+	attributes["application"] = config.Application
+	attributes["environment"] = config.Environment
 
 	targetingKey := hookContext.EvaluationContext().TargetingKey()
 	if targetingKey == "" {
-		if userID, ok := getUserID(attributes); ok {
-			targetingKey = userID
-		} else {
-			targetingKey = generateTargetingKey(h.provider.config.Application, h.provider.config.Environment)
+		key, err := h.provider.resolveTargetingKey(attributes)
+		if err != nil {
+			return nil, err
 		}
+		targetingKey = key
 	}
 
 	newCtx := openfeature.NewEvaluationContext(
@@ -46,7 +47,10 @@ func (h *ProviderHook) Before(ctx context.Context, hookContext openfeature.HookC
 }
 
 func (h *ProviderHook) After(ctx context.Context, hookContext openfeature.HookContext, details openfeature.InterfaceEvaluationDetails, hookHints openfeature.HookHints) error {
-	if h.provider.config.EnableUsage != nil && !*h.provider.config.EnableUsage {
+	h.provider.recordExposure(ctx, hookContext.EvaluationContext(), details)
+
+	config := h.provider.settings()
+	if !h.provider.usageEnabled() && len(h.provider.telemetrySinks()) == 0 {
 		return nil
 	}
 
@@ -85,8 +89,8 @@ func (h *ProviderHook) After(ctx context.Context, hookContext openfeature.HookCo
 
 	hyphenCtx := EvaluationContext{
 		TargetingKey:     evalCtx.TargetingKey(),
-		Application:      h.provider.config.Application,
-		Environment:      h.provider.config.Environment,
+		Application:      config.Application,
+		Environment:      config.Environment,
 		CustomAttributes: flattenedAttributes,
 	}
 
@@ -96,15 +100,16 @@ func (h *ProviderHook) After(ctx context.Context, hookContext openfeature.HookCo
 			Toggle Evaluation `json:"toggle"`
 		}{
 			Toggle: Evaluation{
-				Key:    details.FlagKey,
-				Value:  details.Value,
-				Type:   typeToString[details.FlagType],
-				Reason: string(details.ResolutionDetail.Reason),
+				Key:     details.FlagKey,
+				Value:   details.Value,
+				Type:    typeToString[details.FlagType],
+				Reason:  string(details.ResolutionDetail.Reason),
+				Variant: details.ResolutionDetail.Variant,
 			},
 		},
 	}
 
-	return h.provider.client.SendTelemetry(payload)
+	return h.provider.sendTelemetry(payload)
 }
 
 var typeToString = map[openfeature.Type]string{
@@ -116,8 +121,32 @@ var typeToString = map[openfeature.Type]string{
 }
 
 func (h *ProviderHook) Error(ctx context.Context, hookContext openfeature.HookContext, err error, hookHints openfeature.HookHints) {
+	logError(ctx, "Error in hook:", err)
+}
+
+// runBeforeHooks runs the Before hooks of p in order on the context of hookContext,
+// as the OpenFeature client would for a provider it evaluates directly, and
+// returns the resulting context.
+func runBeforeHooks(ctx context.Context, p openfeature.FeatureProvider, hookContext openfeature.HookContext, hookHints openfeature.HookHints) (openfeature.EvaluationContext, error) {
+	evalCtx := hookContext.EvaluationContext()
+	for _, hook := range p.Hooks() {
+		hookCtx := openfeature.NewHookContext(hookContext.FlagKey(), hookContext.FlagType(), hookContext.DefaultValue(),
+			hookContext.ClientMetadata(), p.Metadata(), evalCtx)
+		result, err := hook.Before(ctx, hookCtx, hookHints)
+		if err != nil {
+			return evalCtx, err
+		}
+		if result != nil {
+			evalCtx = mergeEvaluationContexts(evalCtx, *result)
+		}
+	}
+	return evalCtx, nil
+}
+
+// logError reports err through the logger stored under the "logger" context key, if any.
+func logError(ctx context.Context, msg string, err error) {
 	if logger, ok := ctx.Value("logger").(interface{ Error(args ...interface{}) }); ok {
-		logger.Error("Error in hook:", err)
+		logger.Error(msg, err)
 	}
 }
 
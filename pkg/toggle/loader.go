@@ -0,0 +1,423 @@
+package toggle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvPublicKey           = "HYPHEN_PUBLIC_KEY"
+	EnvStrictPublicKey     = "HYPHEN_STRICT_PUBLIC_KEY"
+	EnvApplication         = "HYPHEN_APPLICATION"
+	EnvEnvironment         = "HYPHEN_ENVIRONMENT"
+	EnvHorizonUrls         = "HYPHEN_HORIZON_URLS"
+	EnvEnableUsage         = "HYPHEN_ENABLE_USAGE"
+	EnvCacheTTL            = "HYPHEN_CACHE_TTL"
+	EnvIntRounding         = "HYPHEN_INT_ROUNDING"
+	EnvAnonymousKey        = "HYPHEN_ANONYMOUS_KEY"
+	EnvExposures           = "HYPHEN_EXPOSURES"
+	EnvExposureDedupWindow = "HYPHEN_EXPOSURE_DEDUP_WINDOW"
+	EnvTelemetryFile       = "HYPHEN_TELEMETRY_FILE"
+	EnvTelemetryWebhook    = "HYPHEN_TELEMETRY_WEBHOOK"
+	EnvOverrideFile        = "HYPHEN_OVERRIDE_FILE"
+	EnvRequestOverrides    = "HYPHEN_ALLOW_REQUEST_OVERRIDES"
+	// EnvRequestOverrideEnvironments lists the environments that allow request overrides.
+	EnvRequestOverrideEnvironments = "HYPHEN_REQUEST_OVERRIDE_ENVIRONMENTS"
+	// EnvOverridePrefix is followed by a flag key, as in HYPHEN_TOGGLE_OVERRIDE_<flag>=value.
+	EnvOverridePrefix = "HYPHEN_TOGGLE_OVERRIDE_"
+)
+
+// ConfigFieldError reports an invalid configuration value. Field is the environment
+// variable or file key the value came from.
+type ConfigFieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *ConfigFieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+func (e *ConfigFieldError) Unwrap() error {
+	return e.Err
+}
+
+// configSource is the serialized form of Config shared by every configuration source.
+// Durations are given as Go duration strings such as "5m", or as a number of seconds.
+type configSource struct {
+	PublicKey             string          `json:"publicKey" yaml:"publicKey" toml:"publicKey"`
+	StrictPublicKey       bool            `json:"strictPublicKey" yaml:"strictPublicKey" toml:"strictPublicKey"`
+	Application           string          `json:"application" yaml:"application" toml:"application"`
+	Environment           string          `json:"environment" yaml:"environment" toml:"environment"`
+	HorizonUrls           []string        `json:"horizonUrls" yaml:"horizonUrls" toml:"horizonUrls"`
+	EnableUsage           *bool           `json:"enableUsage" yaml:"enableUsage" toml:"enableUsage"`
+	Cache                 *cacheSource    `json:"cache" yaml:"cache" toml:"cache"`
+	IntRounding           string          `json:"intRounding" yaml:"intRounding" toml:"intRounding"`
+	AnonymousKey          string          `json:"anonymousKey" yaml:"anonymousKey" toml:"anonymousKey"`
+	Exposures             *exposureSource `json:"exposures" yaml:"exposures" toml:"exposures"`
+	Telemetry             telemetrySource `json:"telemetry" yaml:"telemetry" toml:"telemetry"`
+	Overrides             []Override      `json:"overrides" yaml:"overrides" toml:"overrides"`
+	OverrideFile          string          `json:"overrideFile" yaml:"overrideFile" toml:"overrideFile"`
+	AllowRequestOverrides bool            `json:"allowRequestOverrides" yaml:"allowRequestOverrides" toml:"allowRequestOverrides"`
+	// RequestOverrideEnvironments lists the environments that allow request overrides.
+	RequestOverrideEnvironments []string `json:"requestOverrideEnvironments" yaml:"requestOverrideEnvironments" toml:"requestOverrideEnvironments"`
+}
+
+type cacheSource struct {
+	TTL interface{} `json:"ttl" yaml:"ttl" toml:"ttl"`
+}
+
+type exposureSource struct {
+	DedupWindow interface{} `json:"dedupWindow" yaml:"dedupWindow" toml:"dedupWindow"`
+}
+
+type telemetrySource struct {
+	File           string            `json:"file" yaml:"file" toml:"file"`
+	Webhook        string            `json:"webhook" yaml:"webhook" toml:"webhook"`
+	WebhookHeaders map[string]string `json:"webhookHeaders" yaml:"webhookHeaders" toml:"webhookHeaders"`
+}
+
+// fileFields names the Config fields as they appear in configuration files.
+var fileFields = map[string]string{
+	"publicKey":        "publicKey",
+	"application":      "application",
+	"environment":      "environment",
+	"enableUsage":      "enableUsage",
+	"cacheTTL":         "cache.ttl",
+	"intRounding":      "intRounding",
+	"anonymousKey":     "anonymousKey",
+	"exposures":        "exposures",
+	"dedupWindow":      "exposures.dedupWindow",
+	"horizonUrls":      "horizonUrls",
+	"telemetry":        "telemetry.file",
+	"webhook":          "telemetry.webhook",
+	"overrides":        "overrides",
+	"overrideFile":     "overrideFile",
+	"requestOverrides": "allowRequestOverrides",
+}
+
+// envFields names the Config fields as environment variables.
+var envFields = map[string]string{
+	"publicKey":        EnvPublicKey,
+	"application":      EnvApplication,
+	"environment":      EnvEnvironment,
+	"enableUsage":      EnvEnableUsage,
+	"cacheTTL":         EnvCacheTTL,
+	"intRounding":      EnvIntRounding,
+	"anonymousKey":     EnvAnonymousKey,
+	"exposures":        EnvExposures,
+	"dedupWindow":      EnvExposureDedupWindow,
+	"horizonUrls":      EnvHorizonUrls,
+	"telemetry":        EnvTelemetryFile,
+	"webhook":          EnvTelemetryWebhook,
+	"overrides":        EnvOverridePrefix + "*",
+	"overrideFile":     EnvOverrideFile,
+	"requestOverrides": EnvRequestOverrides,
+}
+
+// ConfigFromEnv builds a Config from HYPHEN_* environment variables and validates it.
+// Lists are comma separated, and setting HYPHEN_EXPOSURE_DEDUP_WINDOW enables
+// exposures just like HYPHEN_EXPOSURES=true. Every HYPHEN_TOGGLE_OVERRIDE_<flag>
+// variable adds a global override, see OverridesFromEnv.
+func ConfigFromEnv() (Config, error) {
+	src := configSource{
+		PublicKey:    os.Getenv(EnvPublicKey),
+		Application:  os.Getenv(EnvApplication),
+		Environment:  os.Getenv(EnvEnvironment),
+		IntRounding:  os.Getenv(EnvIntRounding),
+		AnonymousKey: os.Getenv(EnvAnonymousKey),
+		Telemetry: telemetrySource{
+			File:    os.Getenv(EnvTelemetryFile),
+			Webhook: os.Getenv(EnvTelemetryWebhook),
+		},
+		Overrides:    OverridesFromEnv(),
+		OverrideFile: os.Getenv(EnvOverrideFile),
+	}
+
+	if value := os.Getenv(EnvStrictPublicKey); value != "" {
+		strict, err := strconv.ParseBool(value)
+		if err != nil {
+			return Config{}, &ConfigFieldError{Field: EnvStrictPublicKey, Err: err}
+		}
+		src.StrictPublicKey = strict
+	}
+	if urls := os.Getenv(EnvHorizonUrls); urls != "" {
+		src.HorizonUrls = splitList(urls)
+	}
+	if value := os.Getenv(EnvEnableUsage); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return Config{}, &ConfigFieldError{Field: EnvEnableUsage, Err: err}
+		}
+		src.EnableUsage = &enabled
+	}
+	if ttl := os.Getenv(EnvCacheTTL); ttl != "" {
+		src.Cache = &cacheSource{TTL: ttl}
+	}
+	if value := os.Getenv(EnvExposures); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return Config{}, &ConfigFieldError{Field: EnvExposures, Err: err}
+		}
+		if enabled {
+			src.Exposures = &exposureSource{}
+		}
+	}
+	if window := os.Getenv(EnvExposureDedupWindow); window != "" {
+		src.Exposures = &exposureSource{DedupWindow: window}
+	}
+	if value := os.Getenv(EnvRequestOverrides); value != "" {
+		allow, err := strconv.ParseBool(value)
+		if err != nil {
+			return Config{}, &ConfigFieldError{Field: EnvRequestOverrides, Err: err}
+		}
+		src.AllowRequestOverrides = allow
+	}
+	if environments := os.Getenv(EnvRequestOverrideEnvironments); environments != "" {
+		src.RequestOverrideEnvironments = splitList(environments)
+	}
+
+	return src.config(envFields)
+}
+
+// LoadConfig reads a Config from a YAML, JSON or TOML file, chosen by the file
+// extension, and validates it. Unknown keys are rejected.
+func LoadConfig(path string) (Config, error) {
+	var src configSource
+	if err := decodeFile(path, &src, true); err != nil {
+		return Config{}, err
+	}
+	return src.config(fileFields)
+}
+
+// decodeFile decodes a YAML, JSON or TOML file, chosen by the file extension,
+// into v. When strict, keys without a matching struct field are rejected.
+func decodeFile(path string, v interface{}, strict bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(strict)
+		if err := decoder.Decode(v); err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		if strict {
+			decoder.DisallowUnknownFields()
+		}
+		if err := decoder.Decode(v); err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), v)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); strict && len(undecoded) > 0 {
+			return fmt.Errorf("parsing %s: unknown key %q", path, undecoded[0].String())
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedConfigFormat, ext)
+	}
+	return nil
+}
+
+// config converts the source into a validated Config. fields names each field
+// in errors the way the source spells it.
+func (src configSource) config(fields map[string]string) (Config, error) {
+	config := Config{
+		PublicKey:       src.PublicKey,
+		StrictPublicKey: src.StrictPublicKey,
+		Application:     src.Application,
+		Environment:     src.Environment,
+		HorizonUrls:     src.HorizonUrls,
+		EnableUsage:     src.EnableUsage,
+	}
+
+	// A cache section without a TTL caches for DefaultCacheTTL seconds. An explicit
+	// TTL must be within (0, MaxCacheTTL]; zero does not mean "never expire".
+	if src.Cache != nil {
+		ttl := DefaultCacheTTL * time.Second
+		if src.Cache.TTL != nil {
+			var err error
+			if ttl, err = parseDuration(src.Cache.TTL); err == nil {
+				err = validateCacheTTL(ttl)
+			}
+			if err != nil {
+				return Config{}, &ConfigFieldError{Field: fields["cacheTTL"], Err: err}
+			}
+		}
+		config.Cache = &CacheConfig{TTL: ttl}
+	}
+
+	if src.IntRounding != "" {
+		policy, err := parseRoundingPolicy(src.IntRounding)
+		if err != nil {
+			return Config{}, &ConfigFieldError{Field: fields["intRounding"], Err: err}
+		}
+		config.IntRounding = policy
+	}
+
+	if src.AnonymousKey != "" {
+		strategy, err := parseAnonymousKey(src.AnonymousKey)
+		if err != nil {
+			return Config{}, &ConfigFieldError{Field: fields["anonymousKey"], Err: err}
+		}
+		config.AnonymousKey = strategy
+	}
+
+	if src.Exposures != nil {
+		window, err := parseDuration(src.Exposures.DedupWindow)
+		if err != nil {
+			return Config{}, &ConfigFieldError{Field: fields["dedupWindow"], Err: err}
+		}
+		config.Exposures = &ExposureConfig{DedupWindow: window}
+	}
+
+	config.Overrides = src.Overrides
+	if src.OverrideFile != "" {
+		overrides, err := LoadOverrides(src.OverrideFile)
+		if err != nil {
+			return Config{}, &ConfigFieldError{Field: fields["overrideFile"], Err: err}
+		}
+		config.Overrides = append(config.Overrides, overrides...)
+	}
+	config.AllowRequestOverrides = src.AllowRequestOverrides
+	config.RequestOverrideEnvironments = src.RequestOverrideEnvironments
+
+	for _, raw := range src.HorizonUrls {
+		if err := validateHorizonURL(raw); err != nil {
+			return Config{}, &ConfigFieldError{Field: fields["horizonUrls"], Err: err}
+		}
+	}
+
+	// The provider opens the telemetry file, so that loading a config, which a
+	// reload does on every change, never leaves a file open.
+	config.TelemetryFile = src.Telemetry.File
+	if src.Telemetry.Webhook != "" {
+		if err := validateWebhookURL(src.Telemetry.Webhook); err != nil {
+			return Config{}, &ConfigFieldError{Field: fields["webhook"], Err: err}
+		}
+		config.TelemetryWebhook = src.Telemetry.Webhook
+		config.TelemetryWebhookHeaders = src.Telemetry.WebhookHeaders
+	}
+
+	if err := validateConfig(config); err != nil {
+		return Config{}, &ConfigFieldError{Field: fields[configErrorField(err)], Err: err}
+	}
+
+	return config, nil
+}
+
+// configErrorField returns the field a validateConfig error refers to.
+func configErrorField(err error) string {
+	switch {
+	case errors.Is(err, ErrMissingApplication):
+		return "application"
+	case errors.Is(err, ErrMissingEnvironment), errors.Is(err, ErrInvalidEnvironmentFormat):
+		return "environment"
+	case errors.Is(err, ErrInvalidOverride):
+		return "overrides"
+	case errors.Is(err, ErrRequestOverridesNotAllowed):
+		return "requestOverrides"
+	case errors.Is(err, ErrInvalidCacheTTL):
+		return "cacheTTL"
+	case errors.Is(err, ErrInvalidDedupWindow):
+		return "dedupWindow"
+	default:
+		return "publicKey"
+	}
+}
+
+// parseDuration accepts a Go duration string or a number of seconds. nil is zero.
+func parseDuration(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case string:
+		if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+			return time.Duration(seconds * float64(time.Second)), nil
+		}
+		return time.ParseDuration(v)
+	case int:
+		return time.Duration(v) * time.Second, nil
+	case int64:
+		return time.Duration(v) * time.Second, nil
+	case float64:
+		return time.Duration(v * float64(time.Second)), nil
+	}
+	return 0, fmt.Errorf("invalid duration %v", value)
+}
+
+func parseRoundingPolicy(value string) (RoundingPolicy, error) {
+	switch strings.ToLower(value) {
+	case "reject":
+		return RoundingReject, nil
+	case "truncate":
+		return RoundingTruncate, nil
+	case "nearest":
+		return RoundingNearest, nil
+	case "floor":
+		return RoundingFloor, nil
+	case "ceil":
+		return RoundingCeil, nil
+	}
+	return 0, fmt.Errorf("unknown rounding policy %q, expected reject, truncate, nearest, floor or ceil", value)
+}
+
+// parseAnonymousKey parses "random", "process", "reject" or "hashed:<path>,<path>".
+// loadedAnonymousKey is an anonymous key strategy parsed from a configuration
+// source. It keeps the setting so that reloads can tell whether it changed.
+type loadedAnonymousKey struct {
+	AnonymousKeyStrategy
+	setting string
+}
+
+func parseAnonymousKey(value string) (AnonymousKeyStrategy, error) {
+	name, args, _ := strings.Cut(value, ":")
+	var strategy AnonymousKeyStrategy
+	switch strings.ToLower(name) {
+	case "random":
+		strategy = RandomAnonymousKey()
+	case "process":
+		strategy = ProcessAnonymousKey()
+	case "reject":
+		strategy = RejectAnonymous()
+	case "hashed":
+		paths := splitList(args)
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("hashed anonymous keys need at least one attribute, such as \"hashed:ipAddress,userAgent\"")
+		}
+		strategy = HashedAnonymousKey(paths...)
+	}
+	if strategy != nil {
+		return loadedAnonymousKey{AnonymousKeyStrategy: strategy, setting: value}, nil
+	}
+	return nil, fmt.Errorf("unknown anonymous key strategy %q, expected random, process, reject or hashed:<attributes>", value)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
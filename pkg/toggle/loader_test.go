@@ -0,0 +1,349 @@
+package toggle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvPublicKey, "public_key")
+	t.Setenv(EnvApplication, "test-app")
+	t.Setenv(EnvEnvironment, "production")
+	t.Setenv(EnvHorizonUrls, "https://a.example.com, https://b.example.com")
+	t.Setenv(EnvEnableUsage, "false")
+	t.Setenv(EnvCacheTTL, "5m")
+	t.Setenv(EnvIntRounding, "nearest")
+	t.Setenv(EnvAnonymousKey, "hashed:ipAddress,userAgent")
+	t.Setenv(EnvExposureDedupWindow, "3600")
+	t.Setenv(EnvTelemetryWebhook, "https://hooks.example.com")
+
+	config, err := ConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "public_key", config.PublicKey)
+	assert.Equal(t, "test-app", config.Application)
+	assert.Equal(t, "production", config.Environment)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, config.HorizonUrls)
+	assert.False(t, *config.EnableUsage)
+	assert.Equal(t, 5*time.Minute, config.Cache.TTL)
+	assert.Equal(t, RoundingNearest, config.IntRounding)
+	assert.NotNil(t, config.AnonymousKey)
+	assert.Equal(t, time.Hour, config.Exposures.DedupWindow)
+	assert.Equal(t, "https://hooks.example.com", config.TelemetryWebhook)
+}
+
+func TestConfigFromEnv_Cache(t *testing.T) {
+	server, calls := newHorizonServer(t, "cached")
+	t.Setenv(EnvPublicKey, "public_key")
+	t.Setenv(EnvApplication, "test-app")
+	t.Setenv(EnvEnvironment, "test-env")
+	t.Setenv(EnvHorizonUrls, server.URL)
+	t.Setenv(EnvEnableUsage, "false")
+	t.Setenv(EnvCacheTTL, "5m")
+
+	config, err := ConfigFromEnv()
+	assert.NoError(t, err)
+	p, err := NewProvider(config)
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		res := p.StringEvaluation(context.Background(), "test-flag", "default", openfeature.FlattenedContext{"targetingKey": "user-1"})
+		assert.Equal(t, "cached", res.Value)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestLoadConfig_TelemetryFile(t *testing.T) {
+	dir := t.TempDir()
+	telemetryPath := filepath.Join(dir, "telemetry.ndjson")
+	path := filepath.Join(dir, "config.yaml")
+	content := "publicKey: public_key\napplication: test-app\nenvironment: test-env\nenableUsage: false\ntelemetry:\n  file: " + telemetryPath + "\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Loading the config does not open the telemetry file; the provider does.
+	config, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, telemetryPath, config.TelemetryFile)
+	_, err = os.Stat(telemetryPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	p, err := NewProvider(config)
+	assert.NoError(t, err)
+	var payload TelemetryPayload
+	payload.Data.Toggle.Key = "test-flag"
+	assert.NoError(t, p.sendTelemetry(payload))
+	assert.NoError(t, p.Close())
+
+	data, err := os.ReadFile(telemetryPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"test-flag"`)
+}
+
+func TestConfigFromEnv_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		wantField string
+		wantErr   error
+	}{
+		{
+			name:      "missing application",
+			env:       map[string]string{EnvEnvironment: "production", EnvPublicKey: "key"},
+			wantField: EnvApplication,
+			wantErr:   ErrMissingApplication,
+		},
+		{
+			name:      "invalid environment",
+			env:       map[string]string{EnvApplication: "app", EnvEnvironment: "Production!", EnvPublicKey: "key"},
+			wantField: EnvEnvironment,
+			wantErr:   ErrInvalidEnvironmentFormat,
+		},
+		{
+			name:      "missing public key",
+			env:       map[string]string{EnvApplication: "app", EnvEnvironment: "production"},
+			wantField: EnvPublicKey,
+			wantErr:   ErrMissingPublicKey,
+		},
+		{
+			name:      "invalid bool",
+			env:       map[string]string{EnvEnableUsage: "sometimes"},
+			wantField: EnvEnableUsage,
+		},
+		{
+			name:      "invalid duration",
+			env:       map[string]string{EnvCacheTTL: "soon"},
+			wantField: EnvCacheTTL,
+		},
+		{
+			name:      "negative cache ttl",
+			env:       map[string]string{EnvCacheTTL: "-5m"},
+			wantField: EnvCacheTTL,
+			wantErr:   ErrInvalidCacheTTL,
+		},
+		{
+			name:      "zero cache ttl",
+			env:       map[string]string{EnvCacheTTL: "0"},
+			wantField: EnvCacheTTL,
+			wantErr:   ErrInvalidCacheTTL,
+		},
+		{
+			name:      "cache ttl above maximum",
+			env:       map[string]string{EnvCacheTTL: "25h"},
+			wantField: EnvCacheTTL,
+			wantErr:   ErrInvalidCacheTTL,
+		},
+		{
+			name:      "invalid rounding",
+			env:       map[string]string{EnvIntRounding: "up"},
+			wantField: EnvIntRounding,
+		},
+		{
+			name:      "invalid anonymous key",
+			env:       map[string]string{EnvAnonymousKey: "hashed:"},
+			wantField: EnvAnonymousKey,
+		},
+		{
+			name:      "invalid horizon url",
+			env:       map[string]string{EnvHorizonUrls: "ftp://horizon.example.com"},
+			wantField: EnvHorizonUrls,
+			wantErr:   ErrInvalidHorizonURL,
+		},
+		{
+			name:      "invalid webhook",
+			env:       map[string]string{EnvTelemetryWebhook: "hooks.example.com"},
+			wantField: EnvTelemetryWebhook,
+			wantErr:   ErrInvalidWebhookURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{EnvPublicKey, EnvApplication, EnvEnvironment} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := ConfigFromEnv()
+			var fieldErr *ConfigFieldError
+			assert.ErrorAs(t, err, &fieldErr)
+			assert.Equal(t, tt.wantField, fieldErr.Field)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+publicKey: public_key
+application: test-app
+environment: production
+horizonUrls:
+  - https://a.example.com
+enableUsage: false
+cache:
+  ttl: 5m
+intRounding: floor
+exposures:
+  dedupWindow: 3600
+`,
+		"config.json": `{
+  "publicKey": "public_key",
+  "application": "test-app",
+  "environment": "production",
+  "horizonUrls": ["https://a.example.com"],
+  "enableUsage": false,
+  "cache": {"ttl": "5m"},
+  "intRounding": "floor",
+  "exposures": {"dedupWindow": 3600}
+}`,
+		"config.toml": `
+publicKey = "public_key"
+application = "test-app"
+environment = "production"
+horizonUrls = ["https://a.example.com"]
+enableUsage = false
+intRounding = "floor"
+
+[cache]
+ttl = "5m"
+
+[exposures]
+dedupWindow = 3600
+`,
+	}
+
+	dir := t.TempDir()
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+			config, err := LoadConfig(path)
+			assert.NoError(t, err)
+			assert.Equal(t, "public_key", config.PublicKey)
+			assert.Equal(t, "test-app", config.Application)
+			assert.Equal(t, "production", config.Environment)
+			assert.Equal(t, []string{"https://a.example.com"}, config.HorizonUrls)
+			assert.False(t, *config.EnableUsage)
+			assert.Equal(t, 5*time.Minute, config.Cache.TTL)
+			assert.Equal(t, RoundingFloor, config.IntRounding)
+			assert.Equal(t, time.Hour, config.Exposures.DedupWindow)
+		})
+	}
+}
+
+func TestLoadConfig_DefaultCacheTTL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "publicKey: key\napplication: test-app\nenvironment: production\ncache: {}\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	config, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultCacheTTL*time.Second, config.Cache.TTL)
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		wantField string
+		wantErr   error
+	}{
+		{
+			name:    "unsupported format",
+			file:    "config.ini",
+			content: "application=test-app",
+			wantErr: ErrUnsupportedConfigFormat,
+		},
+		{
+			name:    "unknown yaml key",
+			file:    "config.yaml",
+			content: "aplication: test-app\n",
+		},
+		{
+			name:    "unknown json key",
+			file:    "config.json",
+			content: `{"aplication": "test-app"}`,
+		},
+		{
+			name:    "unknown toml key",
+			file:    "config.toml",
+			content: `aplication = "test-app"`,
+		},
+		{
+			name:      "missing environment",
+			file:      "config.yaml",
+			content:   "application: test-app\npublicKey: key\n",
+			wantField: "environment",
+			wantErr:   ErrMissingEnvironment,
+		},
+		{
+			name:      "invalid cache ttl",
+			file:      "config.json",
+			content:   `{"cache": {"ttl": true}}`,
+			wantField: "cache.ttl",
+		},
+		{
+			name:      "negative cache ttl",
+			file:      "config.yaml",
+			content:   "cache:\n  ttl: -5m\n",
+			wantField: "cache.ttl",
+			wantErr:   ErrInvalidCacheTTL,
+		},
+		{
+			name:      "zero cache ttl",
+			file:      "config.json",
+			content:   `{"cache": {"ttl": 0}}`,
+			wantField: "cache.ttl",
+			wantErr:   ErrInvalidCacheTTL,
+		},
+		{
+			name:      "invalid horizon url",
+			file:      "config.json",
+			content:   `{"horizonUrls": ["horizon.example.com"]}`,
+			wantField: "horizonUrls",
+			wantErr:   ErrInvalidHorizonURL,
+		},
+		{
+			name:      "invalid webhook",
+			file:      "config.yaml",
+			content:   "telemetry:\n  webhook: \"://hooks\"\n",
+			wantField: "telemetry.webhook",
+			wantErr:   ErrInvalidWebhookURL,
+		},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			_, err := LoadConfig(path)
+			assert.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			if tt.wantField != "" {
+				var fieldErr *ConfigFieldError
+				assert.ErrorAs(t, err, &fieldErr)
+				assert.Equal(t, tt.wantField, fieldErr.Field)
+			}
+		})
+	}
+
+	_, err := LoadConfig(filepath.Join(dir, "missing.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,121 @@
+package toggle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// LocalProvider serves flags from an in-memory map with the STATIC reason. It is
+// meant as the last link of a ChainProvider, or for flags that have not moved to
+// Hyphen yet. Every evaluation context gets the same values.
+type LocalProvider struct {
+	name     string
+	response *Response
+}
+
+// NewLocalProvider serves flags, keyed by flag key. Values may be booleans,
+// strings, numbers, or JSON-compatible maps and slices for object flags.
+func NewLocalProvider(flags map[string]interface{}) (*LocalProvider, error) {
+	return newLocalProvider("hyphen-local-provider", flags)
+}
+
+// LoadLocalProvider serves the flags of a YAML, JSON or TOML file mapping flag
+// keys to values. The file is read once.
+func LoadLocalProvider(path string) (*LocalProvider, error) {
+	var flags map[string]interface{}
+	if err := decodeFile(path, &flags, false); err != nil {
+		return nil, err
+	}
+	return newLocalProvider("hyphen-local-provider:"+path, flags)
+}
+
+func newLocalProvider(name string, flags map[string]interface{}) (*LocalProvider, error) {
+	// Round-trip through JSON so values decoded from any format, or built in Go,
+	// reach the coercers in the shape Horizon responses have.
+	data, err := json.Marshal(flags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLocalFlag, err)
+	}
+	var values map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLocalFlag, err)
+	}
+
+	// Check flags in key order so the reported invalid flag is stable.
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	response := &Response{Toggles: make(map[string]Evaluation, len(values))}
+	for _, key := range keys {
+		flagType, err := localFlagType(values[key])
+		if err != nil {
+			return nil, fmt.Errorf("%w: flag %q: %v", ErrInvalidLocalFlag, key, err)
+		}
+		response.Toggles[key] = Evaluation{Key: key, Type: flagType, Value: values[key], Reason: "static"}
+	}
+
+	return &LocalProvider{name: name, response: response}, nil
+}
+
+// localFlagType returns the Horizon type of a JSON-decoded flag value.
+func localFlagType(value interface{}) (string, error) {
+	switch value.(type) {
+	case bool:
+		return "boolean", nil
+	case string:
+		return "string", nil
+	case json.Number:
+		return "number", nil
+	case map[string]interface{}, []interface{}:
+		return "object", nil
+	case nil:
+		return "", fmt.Errorf("value is null")
+	default:
+		return "", fmt.Errorf("unsupported value %T", value)
+	}
+}
+
+func (l *LocalProvider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{
+		Name: l.name,
+	}
+}
+
+func (l *LocalProvider) Hooks() []openfeature.Hook {
+	return []openfeature.Hook{}
+}
+
+func (l *LocalProvider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	value, detail := resolveToggle(l.response, flag, defaultValue, coerceBool)
+	return openfeature.BoolResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+func (l *LocalProvider) StringEvaluation(ctx context.Context, flag string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	value, detail := resolveToggle(l.response, flag, defaultValue, coerceString)
+	return openfeature.StringResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+func (l *LocalProvider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	value, detail := resolveToggle(l.response, flag, defaultValue, coerceFloat)
+	return openfeature.FloatResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+func (l *LocalProvider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	value, detail := resolveToggle(l.response, flag, defaultValue, coerceInt(RoundingReject))
+	return openfeature.IntResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+func (l *LocalProvider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	value, detail := resolveToggle(l.response, flag, defaultValue, coerceObject)
+	return openfeature.InterfaceResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
@@ -0,0 +1,71 @@
+package toggle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalProvider(t *testing.T) {
+	p, err := NewLocalProvider(map[string]interface{}{
+		"bool-flag":   true,
+		"string-flag": "blue",
+		"int-flag":    42,
+		"float-flag":  1.5,
+		"object-flag": map[string]interface{}{"limit": 10},
+	})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	boolRes := p.BooleanEvaluation(ctx, "bool-flag", false, nil)
+	assert.True(t, boolRes.Value)
+	assert.Equal(t, openfeature.StaticReason, boolRes.Reason)
+
+	assert.Equal(t, "blue", p.StringEvaluation(ctx, "string-flag", "", nil).Value)
+	assert.Equal(t, int64(42), p.IntEvaluation(ctx, "int-flag", 0, nil).Value)
+	assert.Equal(t, 1.5, p.FloatEvaluation(ctx, "float-flag", 0, nil).Value)
+	assert.Equal(t, map[string]interface{}{"limit": float64(10)}, p.ObjectEvaluation(ctx, "object-flag", nil, nil).Value)
+
+	missing := p.StringEvaluation(ctx, "missing-flag", "default", nil)
+	assert.Equal(t, "default", missing.Value)
+	assert.Equal(t, openfeature.FlagNotFoundCode, missing.ResolutionDetail().ErrorCode)
+
+	mismatch := p.IntEvaluation(ctx, "string-flag", 7, nil)
+	assert.Equal(t, int64(7), mismatch.Value)
+	assert.Equal(t, openfeature.TypeMismatchCode, mismatch.ResolutionDetail().ErrorCode)
+
+	_, err = NewLocalProvider(map[string]interface{}{"null-flag": nil})
+	assert.ErrorIs(t, err, ErrInvalidLocalFlag)
+}
+
+func TestLoadLocalProvider(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"flags.json": `{"bool-flag": true, "int-flag": 3, "object-flag": {"tier": "gold"}}`,
+		"flags.yaml": "bool-flag: true\nint-flag: 3\nobject-flag:\n  tier: gold\n",
+		"flags.toml": "bool-flag = true\nint-flag = 3\n[object-flag]\ntier = \"gold\"\n",
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+			p, err := LoadLocalProvider(path)
+			assert.NoError(t, err)
+			assert.Equal(t, "hyphen-local-provider:"+path, p.Metadata().Name)
+			assert.True(t, p.BooleanEvaluation(context.Background(), "bool-flag", false, nil).Value)
+			assert.Equal(t, int64(3), p.IntEvaluation(context.Background(), "int-flag", 0, nil).Value)
+			assert.Equal(t, map[string]interface{}{"tier": "gold"}, p.ObjectEvaluation(context.Background(), "object-flag", nil, nil).Value)
+		})
+	}
+
+	path := filepath.Join(dir, "flags.ini")
+	assert.NoError(t, os.WriteFile(path, []byte("bool-flag=true"), 0o600))
+	_, err := LoadLocalProvider(path)
+	assert.ErrorIs(t, err, ErrUnsupportedConfigFormat)
+}
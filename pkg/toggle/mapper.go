@@ -0,0 +1,279 @@
+package toggle
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// ContextMapper converts a flattened OpenFeature context into the Hyphen
+// EvaluationContext sent to Horizon. The provider always sets TargetingKey,
+// Application and Environment on the result, and fills in a User with the
+// targeting key as ID when the mapper leaves it empty.
+type ContextMapper interface {
+	MapContext(evalCtx openfeature.FlattenedContext) (EvaluationContext, error)
+}
+
+// ContextMapperFunc adapts a function to the ContextMapper interface.
+type ContextMapperFunc func(evalCtx openfeature.FlattenedContext) (EvaluationContext, error)
+
+func (f ContextMapperFunc) MapContext(evalCtx openfeature.FlattenedContext) (EvaluationContext, error) {
+	return f(evalCtx)
+}
+
+// DefaultContextMapper applies the built-in rules: "user" is read as a map with
+// id, email, name and customAttributes, a string "ipAddress" is promoted to
+// EvaluationContext.IPAddress, and every other attribute becomes a custom attribute.
+func DefaultContextMapper() ContextMapper {
+	return ContextMapperFunc(mapDefaultContext)
+}
+
+func mapDefaultContext(evalCtx openfeature.FlattenedContext) (EvaluationContext, error) {
+	targetingKey, _ := evalCtx[openfeature.TargetingKey].(string)
+
+	// Extract user data from the context
+	userData, ok := evalCtx["user"].(map[string]interface{})
+	if !ok {
+		userData = make(map[string]interface{})
+	}
+
+	// Create User struct with data from the nested structure
+	user := &User{
+		ID:    getString(userData, "id", targetingKey),
+		Email: getString(userData, "email", ""),
+		Name:  getString(userData, "name", ""),
+	}
+
+	// Handle user custom attributes
+	if customAttrs, ok := userData["customAttributes"].(map[string]interface{}); ok {
+		user.CustomAttributes = customAttrs
+	} else {
+		user.CustomAttributes = make(map[string]interface{})
+	}
+
+	ctx := EvaluationContext{
+		TargetingKey:     targetingKey,
+		User:             user,
+		CustomAttributes: make(map[string]interface{}),
+	}
+
+	// Add remaining top-level attributes to customAttributes
+	for k, v := range evalCtx {
+		switch k {
+		case openfeature.TargetingKey, "user":
+			continue
+		case "ipAddress":
+			if ip, ok := v.(string); ok {
+				ctx.IPAddress = ip
+				continue
+			}
+			ctx.CustomAttributes[k] = v
+		default:
+			ctx.CustomAttributes[k] = v
+		}
+	}
+
+	return ctx, nil
+}
+
+// Conversion converts a mapped attribute value before it is placed in the Hyphen context.
+type Conversion string
+
+const (
+	// ConvertNone passes the value through unchanged.
+	ConvertNone Conversion = ""
+	// ConvertString formats the value as a string.
+	ConvertString Conversion = "string"
+	// ConvertNumber converts numbers and numeric strings to float64.
+	ConvertNumber Conversion = "number"
+	// ConvertInt converts integral numbers and numeric strings to int64.
+	ConvertInt Conversion = "int"
+	// ConvertBool converts booleans and strings accepted by strconv.ParseBool to bool.
+	ConvertBool Conversion = "bool"
+)
+
+// AttributeMapping moves one attribute of the OpenFeature context into the Hyphen context.
+type AttributeMapping struct {
+	// Source is the dotted path of the attribute in the OpenFeature context, such as "profile.email".
+	Source string
+	// Target is where the value is placed in the Hyphen context:
+	//   - "ipAddress", "user.id", "user.email" and "user.name" set the matching fields
+	//   - "user.<name>" sets a user custom attribute
+	//   - any other "<name>" sets a top-level custom attribute
+	Target string
+	// Convert converts the value before it is placed. Defaults to ConvertNone.
+	Convert Conversion
+	// Required rejects contexts where Source is missing. Missing optional attributes are skipped.
+	Required bool
+}
+
+// MappingConfig configures the declarative context mapper returned by NewAttributeMapper.
+type MappingConfig struct {
+	// Attributes are applied in order, so later mappings win when targets overlap.
+	Attributes []AttributeMapping
+	// DropUnmapped discards attributes that no mapping covers. By default they are
+	// handled by DefaultContextMapper.
+	DropUnmapped bool
+}
+
+// NewAttributeMapper returns a ContextMapper that applies the mappings in config.
+// Top-level attributes used as a mapping source are not copied again as custom attributes.
+func NewAttributeMapper(config MappingConfig) (ContextMapper, error) {
+	for _, mapping := range config.Attributes {
+		if mapping.Source == "" || mapping.Target == "" {
+			return nil, fmt.Errorf("%w: source and target are required", ErrInvalidAttributeMapping)
+		}
+		if strings.TrimPrefix(mapping.Target, "user.") == "" {
+			return nil, fmt.Errorf("%w: invalid target %q", ErrInvalidAttributeMapping, mapping.Target)
+		}
+		switch mapping.Convert {
+		case ConvertNone, ConvertString, ConvertNumber, ConvertInt, ConvertBool:
+		default:
+			return nil, fmt.Errorf("%w: unknown conversion %q", ErrInvalidAttributeMapping, mapping.Convert)
+		}
+	}
+	return &attributeMapper{config: config}, nil
+}
+
+type attributeMapper struct {
+	config MappingConfig
+}
+
+func (m *attributeMapper) MapContext(evalCtx openfeature.FlattenedContext) (EvaluationContext, error) {
+	mapped := make(map[string]bool, len(m.config.Attributes))
+	for _, mapping := range m.config.Attributes {
+		mapped[mapping.Source] = true
+	}
+
+	remaining := openfeature.FlattenedContext{}
+	for k, v := range evalCtx {
+		if k == openfeature.TargetingKey || (!m.config.DropUnmapped && !mapped[k]) {
+			remaining[k] = v
+		}
+	}
+
+	ctx, err := mapDefaultContext(remaining)
+	if err != nil {
+		return EvaluationContext{}, err
+	}
+
+	for _, mapping := range m.config.Attributes {
+		value, ok := lookupPath(evalCtx, mapping.Source)
+		if !ok || value == nil {
+			if mapping.Required {
+				return EvaluationContext{}, fmt.Errorf("%w: %s is required", ErrInvalidContext, mapping.Source)
+			}
+			continue
+		}
+
+		value, err := convertAttribute(value, mapping.Convert)
+		if err != nil {
+			return EvaluationContext{}, fmt.Errorf("%w: %s: %v", ErrInvalidAttributeMapping, mapping.Source, err)
+		}
+
+		if err := placeAttribute(&ctx, mapping.Target, value); err != nil {
+			return EvaluationContext{}, fmt.Errorf("%w: %s: %v", ErrInvalidAttributeMapping, mapping.Source, err)
+		}
+	}
+
+	return ctx, nil
+}
+
+func convertAttribute(value interface{}, conversion Conversion) (interface{}, error) {
+	switch conversion {
+	case ConvertString:
+		return fmt.Sprint(value), nil
+	case ConvertNumber:
+		if s, ok := value.(string); ok {
+			return strconv.ParseFloat(strings.TrimSpace(s), 64)
+		}
+		n, err := attributeNumber(value)
+		if err != nil {
+			return nil, err
+		}
+		return toFloat64(n)
+	case ConvertInt:
+		if s, ok := value.(string); ok {
+			return strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		}
+		n, err := attributeNumber(value)
+		if err != nil {
+			return nil, err
+		}
+		return toInt64(n, RoundingReject)
+	case ConvertBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(strings.TrimSpace(v))
+		}
+		return nil, fmt.Errorf("cannot convert %T to bool", value)
+	default:
+		return value, nil
+	}
+}
+
+// attributeNumber converts a numeric attribute of any Go int, uint or float kind
+// into the json.Number or float64 that toFloat64 and toInt64 accept.
+func attributeNumber(value interface{}) (interface{}, error) {
+	if n, ok := value.(json.Number); ok {
+		return n, nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return json.Number(strconv.FormatInt(v.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return json.Number(strconv.FormatUint(v.Uint(), 10)), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("cannot convert %v to a number", f)
+		}
+		return f, nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to a number", value)
+}
+
+func placeAttribute(ctx *EvaluationContext, target string, value interface{}) error {
+	if target == "ipAddress" {
+		ip, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("ipAddress must be a string, got %T", value)
+		}
+		ctx.IPAddress = ip
+		return nil
+	}
+
+	name, isUser := strings.CutPrefix(target, "user.")
+	if !isUser {
+		ctx.CustomAttributes[name] = value
+		return nil
+	}
+
+	switch name {
+	case "id", "email", "name":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string, got %T", target, value)
+		}
+		switch name {
+		case "id":
+			ctx.User.ID = s
+		case "email":
+			ctx.User.Email = s
+		case "name":
+			ctx.User.Name = s
+		}
+	default:
+		ctx.User.CustomAttributes[name] = value
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package toggle
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultContextMapper_IPAddress(t *testing.T) {
+	got, err := DefaultContextMapper().MapContext(openfeature.FlattenedContext{
+		"targetingKey": "user-123",
+		"ipAddress":    "203.0.113.42",
+		"plan":         "pro",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "203.0.113.42", got.IPAddress)
+	assert.Equal(t, map[string]interface{}{"plan": "pro"}, got.CustomAttributes)
+}
+
+func TestNewAttributeMapper_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping AttributeMapping
+	}{
+		{name: "missing source", mapping: AttributeMapping{Target: "plan"}},
+		{name: "missing target", mapping: AttributeMapping{Source: "plan"}},
+		{name: "empty user target", mapping: AttributeMapping{Source: "plan", Target: "user."}},
+		{name: "unknown conversion", mapping: AttributeMapping{Source: "plan", Target: "plan", Convert: "date"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAttributeMapper(MappingConfig{Attributes: []AttributeMapping{tt.mapping}})
+			assert.ErrorIs(t, err, ErrInvalidAttributeMapping)
+		})
+	}
+}
+
+func TestAttributeMapper_MapContext(t *testing.T) {
+	evalCtx := openfeature.FlattenedContext{
+		"targetingKey": "user-123",
+		"profile": map[string]interface{}{
+			"email": "user@example.com",
+			"name":  "Test User",
+		},
+		"clientIp": "203.0.113.42",
+		"seats":    "25",
+		"beta":     "true",
+		"role":     "admin",
+		"company":  "Acme",
+		"other":    "value",
+	}
+
+	tests := []struct {
+		name    string
+		config  MappingConfig
+		want    EvaluationContext
+		wantErr error
+	}{
+		{
+			name: "renames, placement and conversions",
+			config: MappingConfig{Attributes: []AttributeMapping{
+				{Source: "profile.email", Target: "user.email"},
+				{Source: "profile.name", Target: "user.name"},
+				{Source: "clientIp", Target: "ipAddress"},
+				{Source: "seats", Target: "seatCount", Convert: ConvertInt},
+				{Source: "beta", Target: "beta", Convert: ConvertBool},
+				{Source: "role", Target: "user.role"},
+			}},
+			want: EvaluationContext{
+				TargetingKey: "user-123",
+				IPAddress:    "203.0.113.42",
+				User: &User{
+					ID:               "user-123",
+					Email:            "user@example.com",
+					Name:             "Test User",
+					CustomAttributes: map[string]interface{}{"role": "admin"},
+				},
+				CustomAttributes: map[string]interface{}{
+					"profile": map[string]interface{}{
+						"email": "user@example.com",
+						"name":  "Test User",
+					},
+					"seatCount": int64(25),
+					"beta":      true,
+					"company":   "Acme",
+					"other":     "value",
+				},
+			},
+		},
+		{
+			name: "drop unmapped",
+			config: MappingConfig{
+				DropUnmapped: true,
+				Attributes: []AttributeMapping{
+					{Source: "company", Target: "user.company"},
+					{Source: "seats", Target: "seats", Convert: ConvertNumber},
+				},
+			},
+			want: EvaluationContext{
+				TargetingKey: "user-123",
+				User: &User{
+					ID:               "user-123",
+					CustomAttributes: map[string]interface{}{"company": "Acme"},
+				},
+				CustomAttributes: map[string]interface{}{"seats": float64(25)},
+			},
+		},
+		{
+			name: "missing optional source",
+			config: MappingConfig{DropUnmapped: true, Attributes: []AttributeMapping{
+				{Source: "account.tier", Target: "tier"},
+			}},
+			want: EvaluationContext{
+				TargetingKey:     "user-123",
+				User:             &User{ID: "user-123", CustomAttributes: map[string]interface{}{}},
+				CustomAttributes: map[string]interface{}{},
+			},
+		},
+		{
+			name: "missing required source",
+			config: MappingConfig{Attributes: []AttributeMapping{
+				{Source: "account.tier", Target: "tier", Required: true},
+			}},
+			wantErr: ErrInvalidContext,
+		},
+		{
+			name: "failed conversion",
+			config: MappingConfig{Attributes: []AttributeMapping{
+				{Source: "role", Target: "role", Convert: ConvertInt},
+			}},
+			wantErr: ErrInvalidAttributeMapping,
+		},
+		{
+			name: "non-string user field",
+			config: MappingConfig{Attributes: []AttributeMapping{
+				{Source: "seats", Target: "user.id", Convert: ConvertInt},
+			}},
+			wantErr: ErrInvalidAttributeMapping,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper, err := NewAttributeMapper(tt.config)
+			assert.NoError(t, err)
+
+			got, err := mapper.MapContext(evalCtx)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertAttribute_Numbers(t *testing.T) {
+	type seats int32
+
+	tests := []struct {
+		name       string
+		value      interface{}
+		conversion Conversion
+		want       interface{}
+		wantErr    bool
+	}{
+		{name: "int to number", value: 5, conversion: ConvertNumber, want: float64(5)},
+		{name: "int to int", value: 5, conversion: ConvertInt, want: int64(5)},
+		{name: "int8 to int", value: int8(-5), conversion: ConvertInt, want: int64(-5)},
+		{name: "uint16 to number", value: uint16(7), conversion: ConvertNumber, want: float64(7)},
+		{name: "uint64 to int", value: uint64(42), conversion: ConvertInt, want: int64(42)},
+		{name: "named int kind", value: seats(3), conversion: ConvertInt, want: int64(3)},
+		{name: "float32 to number", value: float32(1.5), conversion: ConvertNumber, want: float64(1.5)},
+		{name: "integral float to int", value: 25.0, conversion: ConvertInt, want: int64(25)},
+		{name: "json number to int", value: json.Number("9"), conversion: ConvertInt, want: int64(9)},
+		{name: "fractional float to int", value: 2.5, conversion: ConvertInt, wantErr: true},
+		{name: "uint64 overflow", value: uint64(math.MaxUint64), conversion: ConvertInt, wantErr: true},
+		{name: "NaN", value: math.NaN(), conversion: ConvertNumber, wantErr: true},
+		{name: "infinity", value: math.Inf(1), conversion: ConvertInt, wantErr: true},
+		{name: "bool", value: true, conversion: ConvertNumber, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertAttribute(tt.value, tt.conversion)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProvider_MissingRequiredAttribute(t *testing.T) {
+	mapper, err := NewAttributeMapper(MappingConfig{Attributes: []AttributeMapping{
+		{Source: "seats", Target: "seats", Convert: ConvertInt, Required: true},
+	}})
+	assert.NoError(t, err)
+
+	p, err := NewProviderWithClient(Config{
+		PublicKey:     "public_" + base64.StdEncoding.EncodeToString([]byte("test-org:proj:random")),
+		Application:   "test-app",
+		Environment:   "test-env",
+		ContextMapper: mapper,
+	}, &MockClient{EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+		return &Response{Toggles: map[string]Evaluation{
+			"flag": {Type: "boolean", Value: ctx.CustomAttributes["seats"] == int64(5)},
+		}}, nil
+	}})
+	assert.NoError(t, err)
+
+	res := p.BooleanEvaluation(context.Background(), "flag", false, openfeature.FlattenedContext{"targetingKey": "user-123", "seats": 5})
+	assert.NoError(t, res.Error())
+	assert.True(t, res.Value)
+
+	res = p.BooleanEvaluation(context.Background(), "flag", false, openfeature.FlattenedContext{"targetingKey": "user-123"})
+	assert.Equal(t, openfeature.InvalidContextCode, res.ResolutionDetail().ErrorCode)
+}
+
+func TestProvider_buildContextWithMapper(t *testing.T) {
+	p := &Provider{
+		config: Config{
+			Application: "test-app",
+			Environment: "test-env",
+			ContextMapper: ContextMapperFunc(func(evalCtx openfeature.FlattenedContext) (EvaluationContext, error) {
+				return EvaluationContext{
+					TargetingKey:     "ignored",
+					CustomAttributes: map[string]interface{}{"tenant": evalCtx["org"]},
+				}, nil
+			}),
+		},
+	}
+
+	got, err := p.buildContext(openfeature.FlattenedContext{"targetingKey": "user-123", "org": "acme"})
+	assert.NoError(t, err)
+	assert.Equal(t, EvaluationContext{
+		TargetingKey:     "user-123",
+		Application:      "test-app",
+		Environment:      "test-env",
+		User:             &User{ID: "user-123"},
+		CustomAttributes: map[string]interface{}{"tenant": "acme"},
+	}, got)
+}
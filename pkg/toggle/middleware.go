@@ -0,0 +1,197 @@
+package toggle
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// MiddlewareConfig configures how ContextMiddleware builds an evaluation context from a request.
+type MiddlewareConfig struct {
+	// TrustedProxies lists the IP addresses or CIDR ranges of proxies whose
+	// X-Forwarded-For header is honoured when determining the client IP.
+	TrustedProxies []string
+	// UserExtractor returns the user making the request, or nil for anonymous requests.
+	// The user's ID becomes the targeting key.
+	UserExtractor func(r *http.Request) *User
+	// Headers maps request header names to evaluation context attribute names.
+	Headers map[string]string
+	// Cookies maps cookie names to evaluation context attribute names.
+	Cookies map[string]string
+	// AllowOverrides reads flag overrides from the X-Hyphen-Override header. They
+	// only apply on providers with Config.AllowRequestOverrides set.
+	AllowOverrides bool
+}
+
+// ContextMiddleware is net/http middleware that stores an evaluation context built
+// from each request as the OpenFeature transaction context, so every evaluation made
+// with the request's context uses it automatically.
+type ContextMiddleware struct {
+	config  MiddlewareConfig
+	proxies []*net.IPNet
+}
+
+func NewContextMiddleware(config MiddlewareConfig) (*ContextMiddleware, error) {
+	m := &ContextMiddleware{config: config}
+	for _, proxy := range config.TrustedProxies {
+		network, err := parseNetwork(proxy)
+		if err != nil {
+			return nil, err
+		}
+		m.proxies = append(m.proxies, network)
+	}
+	return m, nil
+}
+
+// Handler wraps next so that it runs with the request's evaluation context.
+func (m *ContextMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := openfeature.MergeTransactionContext(r.Context(), m.EvaluationContext(r))
+		if m.config.AllowOverrides {
+			if overrides := overridesFromHeader(r.Header); len(overrides) > 0 {
+				ctx = ContextWithOverrides(ctx, overrides)
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// EvaluationContext builds the evaluation context for r in the shape the provider expects.
+func (m *ContextMiddleware) EvaluationContext(r *http.Request) openfeature.EvaluationContext {
+	attributes := make(map[string]interface{})
+
+	if ip := m.clientIP(r); ip != "" {
+		attributes["ipAddress"] = ip
+	}
+
+	for header, attribute := range m.config.Headers {
+		if value := r.Header.Get(header); value != "" {
+			attributes[attribute] = value
+		}
+	}
+
+	for name, attribute := range m.config.Cookies {
+		if cookie, err := r.Cookie(name); err == nil && cookie.Value != "" {
+			attributes[attribute] = cookie.Value
+		}
+	}
+
+	var targetingKey string
+	if m.config.UserExtractor != nil {
+		if user := m.config.UserExtractor(r); user != nil {
+			attributes["user"] = userAttributes(user)
+			targetingKey = user.ID
+		}
+	}
+
+	if targetingKey == "" {
+		return openfeature.NewTargetlessEvaluationContext(attributes)
+	}
+	return openfeature.NewEvaluationContext(targetingKey, attributes)
+}
+
+// clientIP returns the address of the client, walking X-Forwarded-For from the
+// right while the hops are trusted proxies.
+func (m *ContextMiddleware) clientIP(r *http.Request) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+
+	if !m.isTrusted(remote) {
+		return remote
+	}
+
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !m.isTrusted(hop) {
+			return hop
+		}
+		remote = hop
+	}
+	return remote
+}
+
+func (m *ContextMiddleware) isTrusted(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range m.proxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNetwork accepts either a CIDR range or a single IP address.
+func parseNetwork(value string) (*net.IPNet, error) {
+	if strings.Contains(value, "/") {
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+		}
+		return network, nil
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid trusted proxy %q", value)
+	}
+	bits := 128
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 32
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+// userAttributes converts a User into the nested "user" attribute read by buildContext.
+func userAttributes(user *User) map[string]interface{} {
+	attributes := map[string]interface{}{
+		"id": user.ID,
+	}
+	if user.Email != "" {
+		attributes["email"] = user.Email
+	}
+	if user.Name != "" {
+		attributes["name"] = user.Name
+	}
+	if len(user.CustomAttributes) > 0 {
+		attributes["customAttributes"] = user.CustomAttributes
+	}
+	return attributes
+}
+
+// mergeTransactionContext layers evalCtx over the transaction context stored in ctx,
+// matching how the OpenFeature client merges contexts for flag evaluations.
+func mergeTransactionContext(ctx context.Context, evalCtx openfeature.EvaluationContext) openfeature.EvaluationContext {
+	return mergeEvaluationContexts(openfeature.TransactionContext(ctx), evalCtx)
+}
+
+// mergeEvaluationContexts layers overlay over base: overlay attributes win, and
+// its targeting key is used unless empty.
+func mergeEvaluationContexts(base, overlay openfeature.EvaluationContext) openfeature.EvaluationContext {
+	attributes := base.Attributes()
+	for k, v := range overlay.Attributes() {
+		attributes[k] = v
+	}
+
+	targetingKey := overlay.TargetingKey()
+	if targetingKey == "" {
+		targetingKey = base.TargetingKey()
+	}
+	if targetingKey == "" {
+		return openfeature.NewTargetlessEvaluationContext(attributes)
+	}
+	return openfeature.NewEvaluationContext(targetingKey, attributes)
+}
@@ -0,0 +1,130 @@
+package toggle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewContextMiddleware(t *testing.T) {
+	_, err := NewContextMiddleware(MiddlewareConfig{TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1", "::1"}})
+	assert.NoError(t, err)
+
+	_, err = NewContextMiddleware(MiddlewareConfig{TrustedProxies: []string{"10.0.0.0/33"}})
+	assert.Error(t, err)
+
+	_, err = NewContextMiddleware(MiddlewareConfig{TrustedProxies: []string{"proxy.internal"}})
+	assert.Error(t, err)
+}
+
+func TestContextMiddleware_ClientIP(t *testing.T) {
+	m, err := NewContextMiddleware(MiddlewareConfig{TrustedProxies: []string{"10.0.0.0/8"}})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		remoteAddr    string
+		forwardedFor  string
+		wantIPAddress string
+	}{
+		{name: "direct client", remoteAddr: "203.0.113.42:1234", wantIPAddress: "203.0.113.42"},
+		{name: "untrusted peer ignores header", remoteAddr: "203.0.113.42:1234", forwardedFor: "198.51.100.7", wantIPAddress: "203.0.113.42"},
+		{name: "trusted proxy", remoteAddr: "10.0.0.1:1234", forwardedFor: "198.51.100.7", wantIPAddress: "198.51.100.7"},
+		{name: "chain of trusted proxies", remoteAddr: "10.0.0.1:1234", forwardedFor: "198.51.100.7, 10.0.0.2", wantIPAddress: "198.51.100.7"},
+		{name: "spoofed leftmost entry", remoteAddr: "10.0.0.1:1234", forwardedFor: "1.1.1.1, 198.51.100.7", wantIPAddress: "198.51.100.7"},
+		{name: "trusted proxy without header", remoteAddr: "10.0.0.1:1234", wantIPAddress: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			assert.Equal(t, tt.wantIPAddress, m.EvaluationContext(r).Attribute("ipAddress"))
+		})
+	}
+}
+
+func TestContextMiddleware_Handler(t *testing.T) {
+	m, err := NewContextMiddleware(MiddlewareConfig{
+		UserExtractor: func(r *http.Request) *User {
+			if id := r.Header.Get("X-User-ID"); id != "" {
+				return &User{ID: id, Email: "user@example.com"}
+			}
+			return nil
+		},
+		Headers: map[string]string{"X-Region": "region"},
+		Cookies: map[string]string{"plan": "subscriptionLevel"},
+	})
+	assert.NoError(t, err)
+
+	var got openfeature.EvaluationContext
+	handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = openfeature.TransactionContext(r.Context())
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "203.0.113.42:1234"
+	r.Header.Set("X-User-ID", "user-123")
+	r.Header.Set("X-Region", "us-east")
+	r.AddCookie(&http.Cookie{Name: "plan", Value: "premium"})
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.Equal(t, "user-123", got.TargetingKey())
+	assert.Equal(t, "203.0.113.42", got.Attribute("ipAddress"))
+	assert.Equal(t, "us-east", got.Attribute("region"))
+	assert.Equal(t, "premium", got.Attribute("subscriptionLevel"))
+	assert.Equal(t, map[string]interface{}{"id": "user-123", "email": "user@example.com"}, got.Attribute("user"))
+
+	anonymous := httptest.NewRequest(http.MethodGet, "/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), anonymous)
+	assert.Empty(t, got.TargetingKey())
+}
+
+func TestContextMiddleware_UsedByProvider(t *testing.T) {
+	var gotCtx EvaluationContext
+	p := &Provider{
+		client: &MockClient{
+			EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+				gotCtx = ctx
+				return &Response{Toggles: map[string]Evaluation{
+					"test-flag": {Type: "boolean", Value: true},
+				}}, nil
+			},
+		},
+		config: Config{
+			Application: "test-app",
+			Environment: "test-env",
+		},
+	}
+	p.hooks = []openfeature.Hook{NewProviderHook(p)}
+	assert.NoError(t, openfeature.SetNamedProviderAndWait("middleware-test", p))
+	client := openfeature.NewClient("middleware-test")
+
+	m, err := NewContextMiddleware(MiddlewareConfig{
+		UserExtractor: func(r *http.Request) *User { return &User{ID: "user-123"} },
+	})
+	assert.NoError(t, err)
+
+	handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value, err := client.BooleanValue(r.Context(), "test-flag", false, openfeature.EvaluationContext{})
+		assert.NoError(t, err)
+		assert.True(t, value)
+
+		_, err = p.EvaluateAll(r.Context(), openfeature.EvaluationContext{})
+		assert.NoError(t, err)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "203.0.113.42:1234"
+	handler.ServeHTTP(httptest.NewRecorder(), r.WithContext(context.Background()))
+
+	assert.Equal(t, "user-123", gotCtx.TargetingKey)
+	assert.Equal(t, "203.0.113.42", gotCtx.IPAddress)
+}
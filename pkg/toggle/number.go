@@ -0,0 +1,151 @@
+package toggle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// RoundingPolicy controls how IntEvaluation converts non-integral numeric flag values.
+type RoundingPolicy int
+
+const (
+	// RoundingReject resolves non-integral values with a type mismatch error.
+	RoundingReject RoundingPolicy = iota
+	// RoundingTruncate drops the fractional part, rounding toward zero.
+	RoundingTruncate
+	// RoundingNearest rounds to the nearest integer, halves away from zero.
+	RoundingNearest
+	// RoundingFloor rounds toward negative infinity.
+	RoundingFloor
+	// RoundingCeil rounds toward positive infinity.
+	RoundingCeil
+)
+
+// bigFloatPrec is wide enough to hold any int64 and the decimal digits Horizon sends
+// without rounding before the integrality and range checks.
+const bigFloatPrec = 256
+
+// toBigFloat converts a decoded JSON number into an arbitrary-precision float.
+func toBigFloat(v interface{}) (*big.Float, error) {
+	switch n := v.(type) {
+	case json.Number:
+		// big.ParseFloat takes time proportional to the exponent, so magnitudes
+		// beyond float64, which are far outside the int64 range, are rejected first.
+		if _, err := strconv.ParseFloat(n.String(), 64); err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return nil, fmt.Errorf("%w: %s", ErrNumberOverflow, n)
+			}
+			return nil, ErrInvalidFlagType
+		}
+		f, _, err := big.ParseFloat(n.String(), 10, bigFloatPrec, big.ToNearestEven)
+		if err != nil {
+			return nil, ErrInvalidFlagType
+		}
+		return f, nil
+	case float64:
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, ErrInvalidFlagType
+		}
+		return new(big.Float).SetPrec(bigFloatPrec).SetFloat64(n), nil
+	default:
+		return nil, ErrInvalidFlagType
+	}
+}
+
+// toInt64 converts a decoded JSON number into an int64, applying policy to
+// non-integral values and rejecting anything outside the int64 range.
+func toInt64(v interface{}, policy RoundingPolicy) (int64, error) {
+	f, err := toBigFloat(v)
+	if err != nil {
+		return 0, err
+	}
+
+	if !f.IsInt() {
+		if policy == RoundingReject {
+			return 0, fmt.Errorf("%w: %s", ErrNonIntegralNumber, f.Text('g', -1))
+		}
+		f = roundBigFloat(f, policy)
+	}
+
+	i, _ := f.Int(nil)
+	if !i.IsInt64() {
+		return 0, fmt.Errorf("%w: %s", ErrNumberOverflow, f.Text('g', -1))
+	}
+	return i.Int64(), nil
+}
+
+// roundBigFloat rounds a non-integral f to an integral value according to policy.
+func roundBigFloat(f *big.Float, policy RoundingPolicy) *big.Float {
+	truncated, _ := f.Int(nil)
+	result := new(big.Float).SetPrec(bigFloatPrec).SetInt(truncated)
+	one := big.NewFloat(1)
+
+	switch policy {
+	case RoundingFloor:
+		if f.Sign() < 0 {
+			result.Sub(result, one)
+		}
+	case RoundingCeil:
+		if f.Sign() > 0 {
+			result.Add(result, one)
+		}
+	case RoundingNearest:
+		half := new(big.Float).SetPrec(bigFloatPrec).Sub(f, result)
+		half.Abs(half)
+		if half.Cmp(big.NewFloat(0.5)) >= 0 {
+			if f.Sign() < 0 {
+				result.Sub(result, one)
+			} else {
+				result.Add(result, one)
+			}
+		}
+	}
+	return result
+}
+
+// toFloat64 converts a decoded JSON number into a float64 using the full
+// precision of its decimal representation.
+func toFloat64(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := strconv.ParseFloat(n.String(), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrNumberOverflow, n)
+		}
+		return f, nil
+	case float64:
+		return n, nil
+	default:
+		return 0, ErrInvalidFlagType
+	}
+}
+
+// normalizeNumbers replaces json.Number values nested inside an object flag with
+// float64, matching what encoding/json produces by default.
+func normalizeNumbers(v interface{}) interface{} {
+	switch val := v.(type) {
+	case json.Number:
+		if f, err := strconv.ParseFloat(val.String(), 64); err == nil {
+			return f
+		}
+		return val
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = normalizeNumbers(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = normalizeNumbers(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,101 @@
+package toggle
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToInt64(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		policy  RoundingPolicy
+		want    int64
+		wantErr error
+	}{
+		{name: "json integer", value: json.Number("42"), want: 42},
+		{name: "json integer beyond 2^53", value: json.Number("9007199254740993"), want: 9007199254740993},
+		{name: "json max int64", value: json.Number("9223372036854775807"), want: 9223372036854775807},
+		{name: "json min int64", value: json.Number("-9223372036854775808"), want: -9223372036854775808},
+		{name: "json exponent integer", value: json.Number("1e3"), want: 1000},
+		{name: "json overflow", value: json.Number("9223372036854775808"), wantErr: ErrNumberOverflow},
+		{name: "json negative overflow", value: json.Number("-9223372036854775809"), wantErr: ErrNumberOverflow},
+		{name: "json fraction rejected", value: json.Number("3.7"), wantErr: ErrNonIntegralNumber},
+		{name: "json fraction truncated", value: json.Number("3.7"), policy: RoundingTruncate, want: 3},
+		{name: "json negative fraction truncated", value: json.Number("-3.7"), policy: RoundingTruncate, want: -3},
+		{name: "json fraction nearest", value: json.Number("3.5"), policy: RoundingNearest, want: 4},
+		{name: "json fraction nearest down", value: json.Number("3.49"), policy: RoundingNearest, want: 3},
+		{name: "json negative fraction nearest", value: json.Number("-3.5"), policy: RoundingNearest, want: -4},
+		{name: "json fraction floor", value: json.Number("-3.2"), policy: RoundingFloor, want: -4},
+		{name: "json fraction ceil", value: json.Number("3.2"), policy: RoundingCeil, want: 4},
+		{name: "json huge exponent", value: json.Number("1e10000000"), wantErr: ErrNumberOverflow},
+		{name: "json negative huge exponent", value: json.Number("-1e10000000"), wantErr: ErrNumberOverflow},
+		{name: "json tiny exponent truncated", value: json.Number("1e-10000000"), policy: RoundingTruncate, want: 0},
+		{name: "json tiny exponent ceil", value: json.Number("1e-10000000"), policy: RoundingCeil, want: 1},
+		{name: "rounding into overflow", value: json.Number("9223372036854775807.5"), policy: RoundingCeil, wantErr: ErrNumberOverflow},
+		{name: "float64 integer", value: float64(7), want: 7},
+		{name: "float64 fraction rejected", value: 3.7, wantErr: ErrNonIntegralNumber},
+		{name: "float64 overflow", value: 1e19, wantErr: ErrNumberOverflow},
+		{name: "not a number", value: "7", wantErr: ErrInvalidFlagType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toInt64(tt.value, tt.policy)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestToFloat64(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    float64
+		wantErr error
+	}{
+		{name: "json number", value: json.Number("0.1"), want: 0.1},
+		{name: "json integer", value: json.Number("12"), want: 12},
+		{name: "json full precision", value: json.Number("3.141592653589793"), want: 3.141592653589793},
+		{name: "json out of range", value: json.Number("1e400"), wantErr: ErrNumberOverflow},
+		{name: "float64", value: 2.5, want: 2.5},
+		{name: "not a number", value: true, wantErr: ErrInvalidFlagType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toFloat64(tt.value)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalizeNumbers(t *testing.T) {
+	value := map[string]interface{}{
+		"count": json.Number("3"),
+		"nested": map[string]interface{}{
+			"ratio": json.Number("0.5"),
+		},
+		"list": []interface{}{json.Number("1"), "a"},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"count": 3.0,
+		"nested": map[string]interface{}{
+			"ratio": 0.5,
+		},
+		"list": []interface{}{1.0, "a"},
+	}, normalizeNumbers(value))
+}
@@ -0,0 +1,87 @@
+package toggle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// SchemaValidator validates a decoded object flag before it is converted into a Go type.
+// Compiled schemas from most JSON-Schema libraries already satisfy this interface.
+type SchemaValidator interface {
+	Validate(value interface{}) error
+}
+
+// DecodeOption configures how object flags are decoded into Go types.
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	strict bool
+	schema SchemaValidator
+}
+
+// WithStrictFields rejects object flags that contain fields not present in the target type.
+func WithStrictFields() DecodeOption {
+	return func(o *decodeOptions) {
+		o.strict = true
+	}
+}
+
+// WithSchema validates object flags against schema before decoding them.
+func WithSchema(schema SchemaValidator) DecodeOption {
+	return func(o *decodeOptions) {
+		o.schema = schema
+	}
+}
+
+// DecodeObject converts an object flag value, as returned by ObjectEvaluation, into T.
+// The schema, if any, is checked even when value already is a T.
+func DecodeObject[T any](value interface{}, opts ...DecodeOption) (T, error) {
+	var out T
+	options := decodeOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.schema != nil {
+		if err := options.schema.Validate(value); err != nil {
+			return out, fmt.Errorf("%w: schema validation failed: %v", ErrInvalidFlagType, err)
+		}
+	}
+
+	if typed, ok := value.(T); ok {
+		return typed, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return out, fmt.Errorf("%w: %v", ErrInvalidFlagType, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if options.strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(&out); err != nil {
+		return out, fmt.Errorf("%w: %v", ErrInvalidFlagType, err)
+	}
+	return out, nil
+}
+
+// ObjectValue evaluates an object flag through client and decodes it into T.
+// When the flag cannot be decoded, defaultValue is returned with a TYPE_MISMATCH error.
+func ObjectValue[T any](ctx context.Context, client openfeature.IClient, flag string, defaultValue T, evalCtx openfeature.EvaluationContext, opts ...DecodeOption) (T, error) {
+	details, err := client.ObjectValueDetails(ctx, flag, defaultValue, evalCtx)
+	if err != nil {
+		return defaultValue, err
+	}
+
+	value, err := DecodeObject[T](details.Value, opts...)
+	if err != nil {
+		return defaultValue, openfeature.NewTypeMismatchResolutionError(err.Error())
+	}
+	return value, nil
+}
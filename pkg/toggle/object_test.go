@@ -0,0 +1,112 @@
+package toggle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+type testGamma struct {
+	Enabled     bool    `json:"enabled"`
+	Probability float64 `json:"probability"`
+	Settings    struct {
+		MaxIterations int `json:"maxIterations"`
+	} `json:"settings"`
+}
+
+type requireEnabled struct{}
+
+func (requireEnabled) Validate(value interface{}) error {
+	if m, ok := value.(map[string]interface{}); ok {
+		if _, ok := m["enabled"]; ok {
+			return nil
+		}
+	}
+	return errors.New("enabled is required")
+}
+
+func TestDecodeObject(t *testing.T) {
+	value := map[string]interface{}{
+		"enabled":     true,
+		"probability": 0.5,
+		"settings": map[string]interface{}{
+			"maxIterations": 10.0,
+		},
+	}
+
+	got, err := DecodeObject[testGamma](value)
+	assert.NoError(t, err)
+	assert.True(t, got.Enabled)
+	assert.Equal(t, 0.5, got.Probability)
+	assert.Equal(t, 10, got.Settings.MaxIterations)
+
+	withExtra := map[string]interface{}{"enabled": true, "extra": "field"}
+	_, err = DecodeObject[testGamma](withExtra)
+	assert.NoError(t, err)
+	_, err = DecodeObject[testGamma](withExtra, WithStrictFields())
+	assert.ErrorIs(t, err, ErrInvalidFlagType)
+
+	_, err = DecodeObject[testGamma](map[string]interface{}{"enabled": "yes"})
+	assert.ErrorIs(t, err, ErrInvalidFlagType)
+
+	_, err = DecodeObject[testGamma](map[string]interface{}{"probability": 0.1}, WithSchema(requireEnabled{}))
+	assert.ErrorIs(t, err, ErrInvalidFlagType)
+	assert.Contains(t, err.Error(), "enabled is required")
+
+	same := testGamma{Enabled: true}
+	got, err = DecodeObject[testGamma](same)
+	assert.NoError(t, err)
+	assert.Equal(t, same, got)
+
+	// The schema applies even when the value already has the target type.
+	_, err = DecodeObject[map[string]interface{}](map[string]interface{}{"probability": 0.1}, WithSchema(requireEnabled{}))
+	assert.ErrorIs(t, err, ErrInvalidFlagType)
+	asMap, err := DecodeObject[map[string]interface{}](value, WithSchema(requireEnabled{}))
+	assert.NoError(t, err)
+	assert.Equal(t, value, asMap)
+}
+
+func TestObjectValue(t *testing.T) {
+	p := &Provider{
+		client: &MockClient{
+			EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+				return &Response{Toggles: map[string]Evaluation{
+					"gamma": {
+						Type:  "object",
+						Value: map[string]interface{}{"enabled": true, "probability": 0.25},
+					},
+					"broken": {
+						Type:  "object",
+						Value: map[string]interface{}{"enabled": "nope"},
+					},
+				}}, nil
+			},
+		},
+		config: Config{
+			Application: "test-app",
+			Environment: "test-env",
+		},
+	}
+	assert.NoError(t, openfeature.SetNamedProviderAndWait("object-value-test", p))
+	client := openfeature.NewClient("object-value-test")
+	evalCtx := openfeature.NewEvaluationContext("user-123", nil)
+
+	defaultGamma := testGamma{Probability: 1}
+
+	got, err := ObjectValue(context.Background(), client, "gamma", defaultGamma, evalCtx)
+	assert.NoError(t, err)
+	assert.True(t, got.Enabled)
+	assert.Equal(t, 0.25, got.Probability)
+
+	got, err = ObjectValue(context.Background(), client, "broken", defaultGamma, evalCtx)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), string(openfeature.TypeMismatchCode))
+	assert.Equal(t, defaultGamma, got)
+
+	got, err = ObjectValue(context.Background(), client, "missing", defaultGamma, evalCtx)
+	assert.Error(t, err)
+	assert.Equal(t, defaultGamma, got)
+}
@@ -0,0 +1,265 @@
+package toggle
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MaxCacheTTL is the longest cache TTL accepted by NewProviderWithOptions.
+const MaxCacheTTL = 24 * time.Hour
+
+// Option configures a provider built with NewProviderWithOptions.
+type Option func(*Config)
+
+// WithConfig starts from an existing Config, such as one returned by ConfigFromEnv.
+// Options applied after it override its fields.
+func WithConfig(config Config) Option {
+	return func(c *Config) {
+		*c = config
+	}
+}
+
+func WithPublicKey(publicKey string) Option {
+	return func(c *Config) {
+		c.PublicKey = publicKey
+	}
+}
+
+// WithStrictPublicKey rejects public keys that ParsePublicKey cannot parse.
+// ValidateConfig, and so NewProviderWithOptions, always checks the key format.
+func WithStrictPublicKey() Option {
+	return func(c *Config) {
+		c.StrictPublicKey = true
+	}
+}
+
+func WithApplication(application string) Option {
+	return func(c *Config) {
+		c.Application = application
+	}
+}
+
+func WithEnvironment(environment string) Option {
+	return func(c *Config) {
+		c.Environment = environment
+	}
+}
+
+// WithHorizonURLs sets the Horizon URLs, tried in order.
+func WithHorizonURLs(urls ...string) Option {
+	return func(c *Config) {
+		c.HorizonUrls = urls
+	}
+}
+
+// WithUsage enables or disables usage telemetry. Usage is enabled by default.
+func WithUsage(enabled bool) Option {
+	return func(c *Config) {
+		c.EnableUsage = &enabled
+	}
+}
+
+// WithCache caches evaluations for ttl, which must be positive and at most MaxCacheTTL.
+// Contexts are keyed with DefaultCacheKey unless WithCacheKeyGen is used.
+func WithCache(ttl time.Duration) Option {
+	return func(c *Config) {
+		if c.Cache == nil {
+			c.Cache = &CacheConfig{}
+		}
+		c.Cache.TTL = ttl
+	}
+}
+
+// WithCacheKeyGen sets the cache key generator. It enables caching with
+// DefaultCacheTTL when WithCache is not used.
+func WithCacheKeyGen(keyGen func(ctx EvaluationContext) string) Option {
+	return func(c *Config) {
+		if c.Cache == nil {
+			c.Cache = &CacheConfig{TTL: DefaultCacheTTL * time.Second}
+		}
+		c.Cache.KeyGen = keyGen
+	}
+}
+
+func WithIntRounding(policy RoundingPolicy) Option {
+	return func(c *Config) {
+		c.IntRounding = policy
+	}
+}
+
+func WithExposures(exposures ExposureConfig) Option {
+	return func(c *Config) {
+		c.Exposures = &exposures
+	}
+}
+
+// WithTelemetrySinks adds telemetry sinks.
+func WithTelemetrySinks(sinks ...TelemetrySink) Option {
+	return func(c *Config) {
+		c.TelemetrySinks = append(c.TelemetrySinks, sinks...)
+	}
+}
+
+func WithAnonymousKey(strategy AnonymousKeyStrategy) Option {
+	return func(c *Config) {
+		c.AnonymousKey = strategy
+	}
+}
+
+func WithContextMapper(mapper ContextMapper) Option {
+	return func(c *Config) {
+		c.ContextMapper = mapper
+	}
+}
+
+func WithContextSchema(schema *ContextSchema) Option {
+	return func(c *Config) {
+		c.ContextSchema = schema
+	}
+}
+
+// WithOverrides adds overrides that force flag values locally.
+func WithOverrides(overrides ...Override) Option {
+	return func(c *Config) {
+		c.Overrides = append(c.Overrides, overrides...)
+	}
+}
+
+// WithRequestOverrides honours overrides attached to request contexts, such as the
+// X-Hyphen-Override header, when the configured environment is one of environments.
+// List only non-production environments.
+func WithRequestOverrides(environments ...string) Option {
+	return func(c *Config) {
+		c.AllowRequestOverrides = true
+		c.RequestOverrideEnvironments = append(c.RequestOverrideEnvironments, environments...)
+	}
+}
+
+// NewProviderWithOptions builds a provider from options. Usage telemetry is enabled
+// and caching disabled unless configured. The configuration is checked with
+// ValidateConfig, so every problem is reported at once.
+func NewProviderWithOptions(opts ...Option) (*Provider, error) {
+	enableUsage := true
+	config := Config{EnableUsage: &enableUsage}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if err := ValidateConfig(config); err != nil {
+		return nil, err
+	}
+	return NewProvider(config)
+}
+
+// ConfigValidationError lists every problem ValidateConfig found. errors.Is and
+// errors.As match against each of them.
+type ConfigValidationError struct {
+	Errors []*ConfigFieldError
+}
+
+func (e *ConfigValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "invalid config: " + strings.Join(messages, "; ")
+}
+
+func (e *ConfigValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ValidateConfig performs every check NewProvider does plus stricter ones: the
+// public key format even without StrictPublicKey, and Horizon and webhook URL
+// syntax, scheme and duplicates. It returns a *ConfigValidationError, or nil.
+func ValidateConfig(config Config) error {
+	v := &configValidator{}
+	checkConfig(v, config)
+
+	if config.PublicKey != "" && !config.StrictPublicKey {
+		if _, err := ParsePublicKey(config.PublicKey); err != nil {
+			v.add("PublicKey", err)
+		}
+	}
+
+	seen := make(map[string]int, len(config.HorizonUrls))
+	for i, raw := range config.HorizonUrls {
+		field := fmt.Sprintf("HorizonUrls[%d]", i)
+		if err := validateHorizonURL(raw); err != nil {
+			v.add(field, err)
+			continue
+		}
+		normalized := normalizeHorizonURL(raw)
+		if first, ok := seen[normalized]; ok {
+			v.add(field, fmt.Errorf("%w: same as HorizonUrls[%d]", ErrDuplicateHorizonURL, first))
+			continue
+		}
+		seen[normalized] = i
+	}
+
+	if config.TelemetryWebhook != "" {
+		if err := validateWebhookURL(config.TelemetryWebhook); err != nil {
+			v.add("TelemetryWebhook", err)
+		}
+	}
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &ConfigValidationError{Errors: v.errs}
+}
+
+type configValidator struct {
+	errs []*ConfigFieldError
+}
+
+func (v *configValidator) add(field string, err error) {
+	v.errs = append(v.errs, &ConfigFieldError{Field: field, Err: err})
+}
+
+// validateCacheTTL checks that ttl is positive and at most MaxCacheTTL.
+func validateCacheTTL(ttl time.Duration) error {
+	if ttl <= 0 || ttl > MaxCacheTTL {
+		return fmt.Errorf("%w: %s is not within (0, %s]", ErrInvalidCacheTTL, ttl, MaxCacheTTL)
+	}
+	return nil
+}
+
+func validateHorizonURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidHorizonURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: %q must use http or https", ErrInvalidHorizonURL, raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%w: %q has no host", ErrInvalidHorizonURL, raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("%w: %q must not have a query or fragment", ErrInvalidHorizonURL, raw)
+	}
+	return nil
+}
+
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhookURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %q must be an http or https URL", ErrInvalidWebhookURL, raw)
+	}
+	return nil
+}
+
+// normalizeHorizonURL strips trailing slashes so endpoint paths can be appended.
+func normalizeHorizonURL(raw string) string {
+	return strings.TrimRight(raw, "/")
+}
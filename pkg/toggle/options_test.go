@@ -0,0 +1,123 @@
+package toggle
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testPublicKey = "public_" + base64.StdEncoding.EncodeToString([]byte("org123:project:secret"))
+
+func TestNewProviderWithOptions(t *testing.T) {
+	sink := NewMemorySink()
+	p, err := NewProviderWithOptions(
+		WithPublicKey(testPublicKey),
+		WithApplication("test-app"),
+		WithEnvironment("production"),
+		WithHorizonURLs("https://horizon.example.com/"),
+		WithCache(time.Minute),
+		WithIntRounding(RoundingNearest),
+		WithTelemetrySinks(sink),
+	)
+	assert.NoError(t, err)
+	assert.True(t, *p.config.EnableUsage)
+	assert.Equal(t, time.Minute, p.config.Cache.TTL)
+	assert.Equal(t, RoundingNearest, p.config.IntRounding)
+	assert.Equal(t, []TelemetrySink{sink}, p.config.TelemetrySinks)
+	assert.Equal(t, []HorizonEndpoints{{
+		Evaluate:  "https://horizon.example.com/toggle/evaluate",
+		Telemetry: "https://horizon.example.com/toggle/telemetry",
+	}}, p.endpoints)
+}
+
+func TestNewProviderWithOptions_Overrides(t *testing.T) {
+	p, err := NewProviderWithOptions(
+		WithConfig(Config{PublicKey: testPublicKey, Application: "test-app", Environment: "staging"}),
+		WithEnvironment("production"),
+		WithUsage(false),
+		WithCacheKeyGen(func(ctx EvaluationContext) string { return ctx.TargetingKey }),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "production", p.config.Environment)
+	assert.False(t, *p.config.EnableUsage)
+	assert.Equal(t, DefaultCacheTTL*time.Second, p.config.Cache.TTL)
+	assert.NotNil(t, p.config.Cache.KeyGen)
+}
+
+func TestValidateConfig_AllProblems(t *testing.T) {
+	config := Config{
+		PublicKey:   "not base64!",
+		Environment: "Production",
+		HorizonUrls: []string{
+			"https://a.example.com",
+			"ftp://b.example.com",
+			"https://a.example.com/",
+			"https://",
+			"https://c.example.com?debug=true",
+			"://bad",
+		},
+		Cache:     &CacheConfig{TTL: -time.Second},
+		Exposures: &ExposureConfig{DedupWindow: -time.Hour},
+	}
+
+	err := ValidateConfig(config)
+	var validationErr *ConfigValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	fields := make(map[string]error)
+	for _, fieldErr := range validationErr.Errors {
+		fields[fieldErr.Field] = fieldErr.Err
+	}
+	want := map[string]error{
+		"Application":           ErrMissingApplication,
+		"Environment":           ErrInvalidEnvironmentFormat,
+		"PublicKey":             ErrInvalidPublicKey,
+		"HorizonUrls[1]":        ErrInvalidHorizonURL,
+		"HorizonUrls[2]":        ErrDuplicateHorizonURL,
+		"HorizonUrls[3]":        ErrInvalidHorizonURL,
+		"HorizonUrls[4]":        ErrInvalidHorizonURL,
+		"HorizonUrls[5]":        ErrInvalidHorizonURL,
+		"Cache.TTL":             ErrInvalidCacheTTL,
+		"Exposures.DedupWindow": ErrInvalidDedupWindow,
+	}
+	assert.Len(t, fields, len(want))
+	for field, wantErr := range want {
+		assert.ErrorIs(t, fields[field], wantErr, field)
+	}
+
+	assert.ErrorIs(t, err, ErrMissingApplication)
+	assert.ErrorIs(t, err, ErrInvalidCacheTTL)
+	assert.False(t, errors.Is(err, ErrMissingPublicKey))
+}
+
+func TestValidateConfig_CacheTTLBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		wantErr bool
+	}{
+		{name: "zero", ttl: 0, wantErr: true},
+		{name: "minimum", ttl: time.Nanosecond},
+		{name: "maximum", ttl: MaxCacheTTL},
+		{name: "too long", ttl: MaxCacheTTL + time.Second, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateConfig(Config{
+				PublicKey:   testPublicKey,
+				Application: "test-app",
+				Environment: "production",
+				Cache:       &CacheConfig{TTL: tt.ttl},
+			})
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCacheTTL)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package toggle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// ReasonOverride is the reason of evaluations answered by an override.
+const ReasonOverride openfeature.Reason = "OVERRIDE"
+
+// MetadataKeyOverride is set on the FlagMetadata of overridden evaluations to the
+// scope of the override: OverrideScopeRequest, OverrideScopeTargetingKey,
+// OverrideScopeAttributes or OverrideScopeGlobal.
+const MetadataKeyOverride = "override"
+
+// Scopes of an override, from the most to the least specific.
+const (
+	OverrideScopeRequest      = "request"
+	OverrideScopeTargetingKey = "targetingKey"
+	OverrideScopeAttributes   = "attributes"
+	OverrideScopeGlobal       = "global"
+)
+
+// OverrideHeader is the request header ContextMiddleware reads overrides from when
+// MiddlewareConfig.AllowOverrides is set. Each header value is "flag=value".
+const OverrideHeader = "X-Hyphen-Override"
+
+// Override forces the value of a flag without changing its Hyphen configuration.
+// An override with a TargetingKey applies to that key only, one with Attributes
+// applies when every attribute equals the evaluation context's, and one with
+// neither applies to every evaluation.
+type Override struct {
+	Flag  string      `json:"flag" yaml:"flag" toml:"flag"`
+	Value interface{} `json:"value" yaml:"value" toml:"value"`
+	// TargetingKey limits the override to one targeting key.
+	TargetingKey string `json:"targetingKey,omitempty" yaml:"targetingKey,omitempty" toml:"targetingKey,omitempty"`
+	// Attributes limits the override to contexts with these attribute values.
+	// Nested attributes are addressed with dots, such as "user.email".
+	Attributes map[string]interface{} `json:"attributes,omitempty" yaml:"attributes,omitempty" toml:"attributes,omitempty"`
+}
+
+func (o Override) scope() string {
+	switch {
+	case o.TargetingKey != "":
+		return OverrideScopeTargetingKey
+	case len(o.Attributes) > 0:
+		return OverrideScopeAttributes
+	default:
+		return OverrideScopeGlobal
+	}
+}
+
+func (o Override) sameTarget(other Override) bool {
+	return o.Flag == other.Flag &&
+		o.TargetingKey == other.TargetingKey &&
+		reflect.DeepEqual(o.Attributes, other.Attributes)
+}
+
+// Matches reports whether the override applies to evalCtx, ignoring the flag.
+func (o Override) Matches(evalCtx openfeature.FlattenedContext) bool {
+	if o.TargetingKey != "" {
+		if key, _ := evalCtx[openfeature.TargetingKey].(string); key != o.TargetingKey {
+			return false
+		}
+	}
+	for path, want := range o.Attributes {
+		got, ok := lookupAttribute(evalCtx, path)
+		if !ok || !reflect.DeepEqual(normalizeValue(got), normalizeValue(want)) {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupAttribute resolves a dotted attribute path, preferring an exact key.
+func lookupAttribute(attributes map[string]interface{}, path string) (interface{}, bool) {
+	if value, ok := attributes[path]; ok {
+		return value, true
+	}
+	head, rest, ok := strings.Cut(path, ".")
+	if !ok {
+		return nil, false
+	}
+	nested, ok := attributes[head].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookupAttribute(nested, rest)
+}
+
+// overrideEvaluation converts an override value into the evaluation the coercers expect.
+func overrideEvaluation(flag string, value interface{}) (Evaluation, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return Evaluation{}, err
+	}
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return Evaluation{}, err
+	}
+	flagType, err := localFlagType(decoded)
+	if err != nil {
+		return Evaluation{}, err
+	}
+	return Evaluation{Key: flag, Type: flagType, Value: decoded}, nil
+}
+
+func validateOverride(o Override) error {
+	if o.Flag == "" {
+		return fmt.Errorf("%w: flag is required", ErrInvalidOverride)
+	}
+	if _, err := overrideEvaluation(o.Flag, o.Value); err != nil {
+		return fmt.Errorf("%w: flag %q: %v", ErrInvalidOverride, o.Flag, err)
+	}
+	return nil
+}
+
+// overrideSet holds a provider's overrides. Overrides set through the API take
+// precedence over configured ones of the same scope.
+type overrideSet struct {
+	mu         sync.RWMutex
+	set        []Override
+	configured []Override
+}
+
+func (s *overrideSet) lookup(flag string, evalCtx openfeature.FlattenedContext) (Override, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, scope := range []string{OverrideScopeTargetingKey, OverrideScopeAttributes, OverrideScopeGlobal} {
+		for _, overrides := range [][]Override{s.set, s.configured} {
+			for _, o := range overrides {
+				if o.Flag == flag && o.scope() == scope && o.Matches(evalCtx) {
+					return o, true
+				}
+			}
+		}
+	}
+	return Override{}, false
+}
+
+// SetOverrides adds overrides, replacing any set earlier for the same flag and target.
+func (p *Provider) SetOverrides(overrides ...Override) error {
+	for _, o := range overrides {
+		if err := validateOverride(o); err != nil {
+			return err
+		}
+	}
+
+	p.overrides.mu.Lock()
+	defer p.overrides.mu.Unlock()
+	for _, o := range overrides {
+		replaced := false
+		for i, existing := range p.overrides.set {
+			if existing.sameTarget(o) {
+				p.overrides.set[i] = o
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			p.overrides.set = append(p.overrides.set, o)
+		}
+	}
+	return nil
+}
+
+// RemoveOverrides removes every override of flag set through SetOverrides.
+func (p *Provider) RemoveOverrides(flag string) {
+	p.overrides.mu.Lock()
+	defer p.overrides.mu.Unlock()
+	kept := p.overrides.set[:0]
+	for _, o := range p.overrides.set {
+		if o.Flag != flag {
+			kept = append(kept, o)
+		}
+	}
+	p.overrides.set = kept
+}
+
+// ClearOverrides removes every override set through SetOverrides. Overrides from
+// Config.Overrides are kept.
+func (p *Provider) ClearOverrides() {
+	p.overrides.mu.Lock()
+	defer p.overrides.mu.Unlock()
+	p.overrides.set = nil
+}
+
+// Overrides returns the active overrides, those set through SetOverrides first.
+func (p *Provider) Overrides() []Override {
+	p.overrides.mu.RLock()
+	defer p.overrides.mu.RUnlock()
+	return append(append([]Override(nil), p.overrides.set...), p.overrides.configured...)
+}
+
+// setConfiguredOverrides replaces the overrides that came from Config.Overrides.
+func (p *Provider) setConfiguredOverrides(overrides []Override) {
+	p.overrides.mu.Lock()
+	defer p.overrides.mu.Unlock()
+	p.overrides.configured = append([]Override(nil), overrides...)
+}
+
+// override returns the evaluation forced for flag, if any, and the scope of the
+// override that forced it.
+func (p *Provider) override(ctx context.Context, flag string, evalCtx openfeature.FlattenedContext) (Evaluation, string, bool) {
+	if config := p.settings(); config.AllowRequestOverrides && requestOverridesAllowed(config) {
+		if value, ok := requestOverrides(ctx)[flag]; ok {
+			if toggle, err := overrideEvaluation(flag, value); err == nil {
+				return toggle, OverrideScopeRequest, true
+			}
+		}
+	}
+
+	o, ok := p.overrides.lookup(flag, evalCtx)
+	if !ok {
+		return Evaluation{}, "", false
+	}
+	toggle, err := overrideEvaluation(flag, o.Value)
+	if err != nil {
+		return Evaluation{}, "", false
+	}
+	return toggle, o.scope(), true
+}
+
+func overrideDetail(scope string) openfeature.ProviderResolutionDetail {
+	return openfeature.ProviderResolutionDetail{
+		Reason:       ReasonOverride,
+		FlagMetadata: openfeature.FlagMetadata{MetadataKeyOverride: scope},
+	}
+}
+
+type requestOverridesKey struct{}
+
+// ContextWithOverrides returns a context whose evaluations resolve the given flags
+// to the given values, on providers with Config.AllowRequestOverrides set.
+// ContextMiddleware uses it for the X-Hyphen-Override header.
+func ContextWithOverrides(ctx context.Context, overrides map[string]interface{}) context.Context {
+	merged := make(map[string]interface{}, len(overrides))
+	for flag, value := range requestOverrides(ctx) {
+		merged[flag] = value
+	}
+	for flag, value := range overrides {
+		merged[flag] = value
+	}
+	return context.WithValue(ctx, requestOverridesKey{}, merged)
+}
+
+func requestOverrides(ctx context.Context) map[string]interface{} {
+	overrides, _ := ctx.Value(requestOverridesKey{}).(map[string]interface{})
+	return overrides
+}
+
+// overridesFromHeader parses the OverrideHeader values of h. Malformed values are skipped.
+func overridesFromHeader(h http.Header) map[string]interface{} {
+	overrides := make(map[string]interface{})
+	for _, header := range h.Values(OverrideHeader) {
+		flag, value, ok := strings.Cut(header, "=")
+		flag = strings.TrimSpace(flag)
+		if !ok || flag == "" {
+			continue
+		}
+		overrides[flag] = parseOverrideValue(strings.TrimSpace(value))
+	}
+	return overrides
+}
+
+// parseOverrideValue reads a JSON value, falling back to the raw string so that
+// HYPHEN_TOGGLE_OVERRIDE_theme=dark needs no quotes.
+func parseOverrideValue(raw string) interface{} {
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return raw
+	}
+	return value
+}
+
+// LoadOverrides reads a list of overrides from a YAML, JSON or TOML file. TOML files
+// list them as [[overrides]] tables, the other formats as a top-level list.
+func LoadOverrides(path string) ([]Override, error) {
+	var overrides []Override
+	if strings.ToLower(filepath.Ext(path)) == ".toml" {
+		var doc struct {
+			Overrides []Override `toml:"overrides"`
+		}
+		if err := decodeFile(path, &doc, true); err != nil {
+			return nil, err
+		}
+		overrides = doc.Overrides
+	} else if err := decodeFile(path, &overrides, true); err != nil {
+		return nil, err
+	}
+
+	for _, o := range overrides {
+		if err := validateOverride(o); err != nil {
+			return nil, err
+		}
+	}
+	return overrides, nil
+}
+
+// OverridesFromEnv returns a global override for every HYPHEN_TOGGLE_OVERRIDE_<flag>
+// environment variable. Values are parsed as JSON when possible, so "true" and "3"
+// are a boolean and a number, and used as strings otherwise.
+func OverridesFromEnv() []Override {
+	var overrides []Override
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		flag, ok := strings.CutPrefix(name, EnvOverridePrefix)
+		if !ok || flag == "" {
+			continue
+		}
+		overrides = append(overrides, Override{Flag: flag, Value: parseOverrideValue(value)})
+	}
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].Flag < overrides[j].Flag })
+	return overrides
+}
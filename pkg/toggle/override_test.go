@@ -0,0 +1,290 @@
+package toggle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOverrideProvider(t *testing.T, config Config) *Provider {
+	enableUsage := false
+	config.PublicKey = testPublicKey
+	config.Application = "test-app"
+	if config.Environment == "" {
+		config.Environment = "development"
+	}
+	config.EnableUsage = &enableUsage
+
+	p, err := NewProvider(config)
+	assert.NoError(t, err)
+	p.client = &MockClient{
+		EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+			return &Response{Toggles: map[string]Evaluation{
+				"theme":   {Key: "theme", Type: "string", Value: "light"},
+				"limit":   {Key: "limit", Type: "number", Value: 10.0},
+				"enabled": {Key: "enabled", Type: "boolean", Value: false},
+			}}, nil
+		},
+	}
+	return p
+}
+
+func TestProvider_Overrides(t *testing.T) {
+	p := newOverrideProvider(t, Config{})
+	assert.NoError(t, p.SetOverrides(
+		Override{Flag: "theme", Value: "dark"},
+		Override{Flag: "theme", Value: "beta", Attributes: map[string]interface{}{"user.email": "qa@example.com"}},
+		Override{Flag: "theme", Value: "tester", TargetingKey: "user-qa"},
+	))
+
+	tests := []struct {
+		name      string
+		evalCtx   openfeature.FlattenedContext
+		want      string
+		wantScope string
+	}{
+		{
+			name:      "global",
+			evalCtx:   openfeature.FlattenedContext{"targetingKey": "user-1"},
+			want:      "dark",
+			wantScope: OverrideScopeGlobal,
+		},
+		{
+			name: "attribute match",
+			evalCtx: openfeature.FlattenedContext{
+				"targetingKey": "user-2",
+				"user":         map[string]interface{}{"email": "qa@example.com"},
+			},
+			want:      "beta",
+			wantScope: OverrideScopeAttributes,
+		},
+		{
+			name: "targeting key wins",
+			evalCtx: openfeature.FlattenedContext{
+				"targetingKey": "user-qa",
+				"user":         map[string]interface{}{"email": "qa@example.com"},
+			},
+			want:      "tester",
+			wantScope: OverrideScopeTargetingKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := p.StringEvaluation(context.Background(), "theme", "default", tt.evalCtx)
+			assert.Equal(t, tt.want, res.Value)
+			assert.Equal(t, ReasonOverride, res.Reason)
+			assert.Equal(t, tt.wantScope, res.FlagMetadata[MetadataKeyOverride])
+		})
+	}
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+
+	// Overrides are coerced like Horizon values.
+	mismatch := p.IntEvaluation(context.Background(), "theme", 3, evalCtx)
+	assert.Equal(t, int64(3), mismatch.Value)
+	assert.Equal(t, openfeature.TypeMismatchCode, mismatch.ResolutionDetail().ErrorCode)
+
+	// Flags without overrides are resolved by Horizon.
+	limit := p.IntEvaluation(context.Background(), "limit", 0, evalCtx)
+	assert.Equal(t, int64(10), limit.Value)
+	assert.NotEqual(t, ReasonOverride, limit.Reason)
+
+	// Setting the same target replaces the override.
+	assert.NoError(t, p.SetOverrides(Override{Flag: "theme", Value: "solarized"}))
+	assert.Len(t, p.Overrides(), 3)
+	assert.Equal(t, "solarized", p.StringEvaluation(context.Background(), "theme", "default", evalCtx).Value)
+
+	p.RemoveOverrides("theme")
+	assert.Equal(t, "light", p.StringEvaluation(context.Background(), "theme", "default", evalCtx).Value)
+
+	err := p.SetOverrides(Override{Flag: "theme"})
+	assert.ErrorIs(t, err, ErrInvalidOverride)
+}
+
+func TestProvider_ConfiguredOverrides(t *testing.T) {
+	config := Config{Overrides: []Override{{Flag: "enabled", Value: true}, {Flag: "limit", Value: 5}}}
+	p := newOverrideProvider(t, config)
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+
+	assert.True(t, p.BooleanEvaluation(context.Background(), "enabled", false, evalCtx).Value)
+	assert.Equal(t, int64(5), p.IntEvaluation(context.Background(), "limit", 0, evalCtx).Value)
+
+	// API overrides take precedence and survive config updates.
+	assert.NoError(t, p.SetOverrides(Override{Flag: "limit", Value: 7}))
+	assert.Equal(t, int64(7), p.IntEvaluation(context.Background(), "limit", 0, evalCtx).Value)
+
+	config = p.settings()
+	config.Overrides = nil
+	assert.NoError(t, p.UpdateConfig(config))
+	assert.False(t, p.BooleanEvaluation(context.Background(), "enabled", false, evalCtx).Value)
+	assert.Equal(t, int64(7), p.IntEvaluation(context.Background(), "limit", 0, evalCtx).Value)
+
+	p.ClearOverrides()
+	assert.Equal(t, int64(10), p.IntEvaluation(context.Background(), "limit", 0, evalCtx).Value)
+
+	results, err := p.EvaluateAll(context.Background(), openfeature.NewEvaluationContext("user-1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, openfeature.TargetingMatchReason, results["limit"].Reason)
+
+	assert.NoError(t, p.SetOverrides(Override{Flag: "limit", Value: 7}))
+	results, err = p.EvaluateAll(context.Background(), openfeature.NewEvaluationContext("user-1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), results["limit"].Value)
+	assert.Equal(t, ReasonOverride, results["limit"].Reason)
+	assert.Equal(t, OverrideScopeGlobal, results["limit"].FlagMetadata[MetadataKeyOverride])
+}
+
+func TestProvider_RequestOverrides(t *testing.T) {
+	m, err := NewContextMiddleware(MiddlewareConfig{AllowOverrides: true})
+	assert.NoError(t, err)
+
+	evaluate := func(p *Provider) openfeature.StringResolutionDetail {
+		var res openfeature.StringResolutionDetail
+		handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res = p.StringEvaluation(r.Context(), "theme", "default", openfeature.FlattenedContext{"targetingKey": "user-1"})
+		}))
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Add(OverrideHeader, "theme=feature-branch")
+		r.Header.Add(OverrideHeader, `limit={"max": 3}`)
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+		return res
+	}
+
+	allowed := newOverrideProvider(t, Config{AllowRequestOverrides: true, RequestOverrideEnvironments: []string{"development"}})
+	res := evaluate(allowed)
+	assert.Equal(t, "feature-branch", res.Value)
+	assert.Equal(t, ReasonOverride, res.Reason)
+	assert.Equal(t, OverrideScopeRequest, res.FlagMetadata[MetadataKeyOverride])
+
+	ctx := ContextWithOverrides(context.Background(), map[string]interface{}{"limit": 4})
+	assert.Equal(t, int64(4), allowed.IntEvaluation(ctx, "limit", 0, openfeature.FlattenedContext{"targetingKey": "user-1"}).Value)
+
+	disallowed := newOverrideProvider(t, Config{})
+	assert.Equal(t, "light", evaluate(disallowed).Value)
+
+	// Environments are allowed only by listing them, never judged by their name.
+	for _, environment := range []string{"production", "prd", "live", "pevr_abc123", "development"} {
+		_, err = NewProvider(Config{
+			PublicKey:                   "test-key",
+			Application:                 "test-app",
+			Environment:                 environment,
+			AllowRequestOverrides:       true,
+			RequestOverrideEnvironments: []string{"staging"},
+		})
+		assert.ErrorIs(t, err, ErrRequestOverridesNotAllowed, environment)
+	}
+
+	_, err = NewProviderWithOptions(
+		WithPublicKey(testPublicKey),
+		WithApplication("test-app"),
+		WithEnvironment("prd"),
+		WithRequestOverrides("staging"),
+	)
+	assert.ErrorIs(t, err, ErrRequestOverridesNotAllowed)
+
+	_, err = NewProviderWithOptions(
+		WithPublicKey(testPublicKey),
+		WithApplication("test-app"),
+		WithEnvironment("staging"),
+		WithRequestOverrides("staging"),
+	)
+	assert.NoError(t, err)
+}
+
+func TestProvider_RequestOverridesFollowAllowlist(t *testing.T) {
+	p := newOverrideProvider(t, Config{AllowRequestOverrides: true, RequestOverrideEnvironments: []string{"development"}})
+	ctx := ContextWithOverrides(context.Background(), map[string]interface{}{"theme": "dark"})
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	assert.Equal(t, "dark", p.StringEvaluation(ctx, "theme", "default", evalCtx).Value)
+
+	// A configuration that bypassed validation still ignores request overrides
+	// outside the listed environments.
+	p.mu.Lock()
+	p.config.RequestOverrideEnvironments = []string{"staging"}
+	p.mu.Unlock()
+	assert.Equal(t, "light", p.StringEvaluation(ctx, "theme", "default", evalCtx).Value)
+}
+
+func TestProvider_OverrideTelemetry(t *testing.T) {
+	sink := NewMemorySink()
+	p := newOverrideProvider(t, Config{TelemetrySinks: []TelemetrySink{sink}})
+	assert.NoError(t, p.SetOverrides(Override{Flag: "enabled", Value: true}))
+
+	assert.NoError(t, openfeature.SetNamedProviderAndWait("override-test", p))
+	client := openfeature.NewClient("override-test")
+	value, err := client.BooleanValue(context.Background(), "enabled", false, openfeature.NewEvaluationContext("user-1", nil))
+	assert.NoError(t, err)
+	assert.True(t, value)
+
+	telemetry := sink.Telemetry()
+	assert.Len(t, telemetry, 1)
+	assert.Equal(t, string(ReasonOverride), telemetry[0].Data.Toggle.Reason)
+}
+
+func TestLoadOverrides(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"overrides.json": `[{"flag": "theme", "value": "dark"}, {"flag": "limit", "value": 3, "targetingKey": "user-qa"}]`,
+		"overrides.yaml": "- flag: theme\n  value: dark\n- flag: limit\n  value: 3\n  targetingKey: user-qa\n",
+		"overrides.toml": "[[overrides]]\nflag = \"theme\"\nvalue = \"dark\"\n\n[[overrides]]\nflag = \"limit\"\nvalue = 3\ntargetingKey = \"user-qa\"\n",
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+			overrides, err := LoadOverrides(path)
+			assert.NoError(t, err)
+			assert.Len(t, overrides, 2)
+			for _, o := range overrides {
+				if o.Flag == "limit" {
+					assert.Equal(t, "user-qa", o.TargetingKey)
+				}
+			}
+		})
+	}
+
+	path := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`[{"value": true}]`), 0o600))
+	_, err := LoadOverrides(path)
+	assert.ErrorIs(t, err, ErrInvalidOverride)
+}
+
+func TestConfigFromEnv_Overrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`[{"flag": "beta", "value": true, "targetingKey": "user-qa"}]`), 0o600))
+
+	t.Setenv(EnvPublicKey, "public_key")
+	t.Setenv(EnvApplication, "test-app")
+	t.Setenv(EnvEnvironment, "staging")
+	t.Setenv(EnvOverridePrefix+"theme", "dark")
+	t.Setenv(EnvOverridePrefix+"limit", "3")
+	t.Setenv(EnvOverrideFile, path)
+	t.Setenv(EnvRequestOverrides, "true")
+	t.Setenv(EnvRequestOverrideEnvironments, "staging, qa")
+
+	config, err := ConfigFromEnv()
+	assert.NoError(t, err)
+	assert.True(t, config.AllowRequestOverrides)
+	assert.Equal(t, []string{"staging", "qa"}, config.RequestOverrideEnvironments)
+	assert.Equal(t, []Override{
+		{Flag: "limit", Value: float64(3)},
+		{Flag: "theme", Value: "dark"},
+		{Flag: "beta", Value: true, TargetingKey: "user-qa"},
+	}, config.Overrides)
+
+	t.Setenv(EnvEnvironment, "live")
+	_, err = ConfigFromEnv()
+	assert.ErrorIs(t, err, ErrRequestOverridesNotAllowed)
+	var fieldErr *ConfigFieldError
+	assert.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, EnvRequestOverrides, fieldErr.Field)
+}
@@ -2,10 +2,9 @@ package toggle
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
 	"regexp"
-	"strings"
+	"sync"
 
 	"github.com/open-feature/go-sdk/openfeature"
 )
@@ -16,57 +15,54 @@ var (
 )
 
 type Provider struct {
+	client ClientInterface
+	hooks  []openfeature.Hook
+	events chan openfeature.Event
+
+	// mu guards the fields below, which UpdateConfig replaces.
+	mu        sync.RWMutex
 	config    Config
-	client    ClientInterface
 	endpoints []HorizonEndpoints
-	hooks     []openfeature.Hook
-}
-
-func extractOrgID(publicKey string) (string, error) {
-	key := strings.TrimPrefix(publicKey, "public_")
-
-	decoded, err := base64.StdEncoding.DecodeString(key)
-	if err != nil {
-		return "", fmt.Errorf("failed to decode public key: %w", err)
-	}
+	exposures *exposureTracker
+	sinks     configuredSinks
 
-	parts := strings.Split(string(decoded), ":")
-	if len(parts) < 2 {
-		return "", fmt.Errorf("invalid key format: insufficient parts")
-	}
-
-	orgID := parts[0]
-	if !orgIDRegex.MatchString(orgID) {
-		return "", fmt.Errorf("invalid orgID format")
-	}
-
-	return orgID, nil
+	overrides overrideSet
 }
 
 func NewProvider(config Config) (*Provider, error) {
+	return NewProviderWithClient(config, nil)
+}
+
+// NewProviderWithClient creates a provider that evaluates flags through client
+// instead of Horizon, such as the in-memory client of the toggletest package.
+// A nil client creates the usual Horizon client.
+func NewProviderWithClient(config Config, client ClientInterface) (*Provider, error) {
 	if err := validateConfig(config); err != nil {
 		return nil, err
 	}
 
-	horizonUrls := config.HorizonUrls
-	if len(horizonUrls) == 0 {
-		url := fmt.Sprintf("https://%s", defaultHorizonURL)
-		if orgID, err := extractOrgID(config.PublicKey); err == nil && orgID != "" {
-			url = fmt.Sprintf("https://%s.%s", orgID, defaultHorizonURL)
-		}
-		horizonUrls = []string{url}
-	}
-
 	p := &Provider{
 		config:    config,
-		endpoints: newEndpoints(horizonUrls),
+		endpoints: newEndpoints(horizonURLs(config)),
+		events:    make(chan openfeature.Event, 10),
 	}
 
-	client, err := newClient(config, p.endpoints)
+	if client == nil {
+		horizonClient, err := newClient(config, p.endpoints)
+		if err != nil {
+			return nil, err
+		}
+		client = horizonClient
+	}
+	p.client = client
+
+	sinks, err := configuredSinks{}.reconfigure(config)
 	if err != nil {
 		return nil, err
 	}
-	p.client = client
+	p.sinks = sinks
+	p.exposures = p.newExposures(config, sinks)
+	p.setConfiguredOverrides(config.Overrides)
 
 	hook := NewProviderHook(p)
 	p.hooks = []openfeature.Hook{hook}
@@ -74,255 +70,88 @@ func NewProvider(config Config) (*Provider, error) {
 	return p, nil
 }
 
-func (p *Provider) Metadata() openfeature.Metadata {
-	return openfeature.Metadata{
-		Name: "hyphen-provider",
+// horizonURLs returns the configured Horizon URLs, defaulting to the organization's
+// Horizon instance derived from the public key. Keys that cannot be parsed fall back
+// to the shared Horizon host; set StrictPublicKey to reject them instead.
+func horizonURLs(config Config) []string {
+	if len(config.HorizonUrls) > 0 {
+		return config.HorizonUrls
 	}
-}
-func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
-	hyphenCtx, err := p.buildContext(evalCtx)
-	if err != nil {
-		return openfeature.BoolResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				Reason:          openfeature.ErrorReason,
-				ResolutionError: openfeature.NewParseErrorResolutionError(err.Error()),
-			},
-		}
-	}
-
-	eval, err := p.client.Evaluate(hyphenCtx)
-	if err != nil {
-		return openfeature.BoolResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				Reason:          openfeature.ErrorReason,
-				ResolutionError: openfeature.NewGeneralResolutionError(err.Error()),
-			},
-		}
+	url := fmt.Sprintf("https://%s", defaultHorizonURL)
+	if orgID, err := extractOrgID(config.PublicKey); err == nil {
+		url = fmt.Sprintf("https://%s.%s", orgID, defaultHorizonURL)
 	}
+	return []string{url}
+}
 
-	if toggle, ok := eval.Toggles[flag]; ok && toggle.Type == "boolean" {
-		if value, ok := toggle.Value.(bool); ok {
-			return openfeature.BoolResolutionDetail{
-				Value: value,
-				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-					Reason: openfeature.TargetingMatchReason,
-				},
-			}
-		}
+// newExposures builds the exposure tracker for config, or nil when exposures are disabled.
+func (p *Provider) newExposures(config Config, configured configuredSinks) *exposureTracker {
+	if config.Exposures == nil {
+		return nil
 	}
+	return newExposureTracker(*config.Exposures, exposureSinks(telemetrySinks(config, configured))...)
+}
 
-	return openfeature.BoolResolutionDetail{
-		Value: defaultValue,
-		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			Reason:          openfeature.ErrorReason,
-			ResolutionError: openfeature.NewTypeMismatchResolutionError("invalid flag type"),
-		},
-	}
+// settings returns the current configuration. UpdateConfig may replace it concurrently.
+func (p *Provider) settings() Config {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.config
 }
 
-func (p *Provider) StringEvaluation(
-	ctx context.Context,
-	flag string,
-	defaultValue string,
-	evalCtx openfeature.FlattenedContext,
-) openfeature.StringResolutionDetail {
-	hyphenCtx, err := p.buildContext(evalCtx)
-	if err != nil {
-		return openfeature.StringResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: openfeature.NewParseErrorResolutionError(err.Error()),
-				Reason:          openfeature.ErrorReason,
-			},
-		}
-	}
+// Close releases the telemetry file opened for Config.TelemetryFile. The provider
+// must not be used afterwards.
+func (p *Provider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	err := p.sinks.closeReplaced(configuredSinks{})
+	p.sinks = configuredSinks{}
+	return err
+}
 
-	eval, err := p.client.Evaluate(hyphenCtx)
-	if err != nil {
-		return openfeature.StringResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: openfeature.NewGeneralResolutionError(err.Error()),
-				Reason:          openfeature.ErrorReason,
-			},
-		}
+func (p *Provider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{
+		Name: "hyphen-provider",
 	}
+}
 
-	if toggle, ok := eval.Toggles[flag]; ok && toggle.Type == "string" {
-		if value, ok := toggle.Value.(string); ok {
-			return openfeature.StringResolutionDetail{
-				Value: value,
-				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-					Reason: openfeature.TargetingMatchReason,
-				},
-			}
-		}
+func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	value, detail := resolve(ctx, p, flag, defaultValue, evalCtx, coerceBool)
+	return openfeature.BoolResolutionDetail{
+		Value:                    value,
+		ProviderResolutionDetail: detail,
 	}
+}
 
+func (p *Provider) StringEvaluation(ctx context.Context, flag string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	value, detail := resolve(ctx, p, flag, defaultValue, evalCtx, coerceString)
 	return openfeature.StringResolutionDetail{
-		Value: defaultValue,
-		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			ResolutionError: openfeature.NewTypeMismatchResolutionError("invalid flag type"),
-			Reason:          openfeature.ErrorReason,
-		},
+		Value:                    value,
+		ProviderResolutionDetail: detail,
 	}
 }
-func (p *Provider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
-	hyphenCtx, err := p.buildContext(evalCtx)
-	if err != nil {
-		return openfeature.FloatResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: openfeature.NewParseErrorResolutionError(err.Error()),
-				Reason:          openfeature.ErrorReason,
-			},
-		}
-	}
-
-	eval, err := p.client.Evaluate(hyphenCtx)
-	if err != nil {
-		return openfeature.FloatResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: openfeature.NewGeneralResolutionError(err.Error()),
-				Reason:          openfeature.ErrorReason,
-			},
-		}
-	}
-
-	if toggle, ok := eval.Toggles[flag]; ok && toggle.Type == "number" {
-		switch v := toggle.Value.(type) {
-		case float64:
-			return openfeature.FloatResolutionDetail{
-				Value: v,
-				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-					Reason: openfeature.TargetingMatchReason,
-				},
-			}
-		case int:
-			return openfeature.FloatResolutionDetail{
-				Value: float64(v),
-				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-					Reason: openfeature.TargetingMatchReason,
-				},
-			}
-		case int64:
-			return openfeature.FloatResolutionDetail{
-				Value: float64(v),
-				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-					Reason: openfeature.TargetingMatchReason,
-				},
-			}
-		}
-	}
 
+func (p *Provider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	value, detail := resolve(ctx, p, flag, defaultValue, evalCtx, coerceFloat)
 	return openfeature.FloatResolutionDetail{
-		Value: defaultValue,
-		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			ResolutionError: openfeature.NewTypeMismatchResolutionError(ErrInvalidFlagType.Error()),
-			Reason:          openfeature.ErrorReason,
-		},
+		Value:                    value,
+		ProviderResolutionDetail: detail,
 	}
 }
 
 func (p *Provider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
-	hyphenCtx, err := p.buildContext(evalCtx)
-	if err != nil {
-		return openfeature.IntResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: openfeature.NewParseErrorResolutionError(err.Error()),
-				Reason:          openfeature.ErrorReason,
-			},
-		}
-	}
-
-	eval, err := p.client.Evaluate(hyphenCtx)
-	if err != nil {
-		return openfeature.IntResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: openfeature.NewGeneralResolutionError(err.Error()),
-				Reason:          openfeature.ErrorReason,
-			},
-		}
-	}
-
-	if toggle, ok := eval.Toggles[flag]; ok && toggle.Type == "number" {
-		switch v := toggle.Value.(type) {
-		case int:
-			return openfeature.IntResolutionDetail{
-				Value: int64(v),
-				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-					Reason: openfeature.TargetingMatchReason,
-				},
-			}
-		case int64:
-			return openfeature.IntResolutionDetail{
-				Value: v,
-				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-					Reason: openfeature.TargetingMatchReason,
-				},
-			}
-		case float64:
-			return openfeature.IntResolutionDetail{
-				Value: int64(v),
-				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-					Reason: openfeature.TargetingMatchReason,
-				},
-			}
-		}
-	}
-
+	value, detail := resolve(ctx, p, flag, defaultValue, evalCtx, coerceInt(p.settings().IntRounding))
 	return openfeature.IntResolutionDetail{
-		Value: defaultValue,
-		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			ResolutionError: openfeature.NewTypeMismatchResolutionError(ErrInvalidFlagType.Error()),
-			Reason:          openfeature.ErrorReason,
-		},
+		Value:                    value,
+		ProviderResolutionDetail: detail,
 	}
 }
 
 func (p *Provider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
-	hyphenCtx, err := p.buildContext(evalCtx)
-	if err != nil {
-		return openfeature.InterfaceResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				Reason:          openfeature.ErrorReason,
-				ResolutionError: openfeature.NewParseErrorResolutionError(err.Error()),
-			},
-		}
-	}
-
-	eval, err := p.client.Evaluate(hyphenCtx)
-	if err != nil {
-		return openfeature.InterfaceResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				Reason:          openfeature.ErrorReason,
-				ResolutionError: openfeature.NewGeneralResolutionError(err.Error()),
-			},
-		}
-	}
-
-	if toggle, ok := eval.Toggles[flag]; ok && toggle.Type == "object" {
-		return openfeature.InterfaceResolutionDetail{
-			Value: toggle.Value,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				Reason: openfeature.TargetingMatchReason,
-			},
-		}
-	}
-
+	value, detail := resolve(ctx, p, flag, defaultValue, evalCtx, coerceObject)
 	return openfeature.InterfaceResolutionDetail{
-		Value: defaultValue,
-		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			Reason:          openfeature.ErrorReason,
-			ResolutionError: openfeature.NewTypeMismatchResolutionError(ErrInvalidFlagType.Error()),
-		},
+		Value:                    value,
+		ProviderResolutionDetail: detail,
 	}
 }
 
@@ -332,42 +161,30 @@ func (p *Provider) buildContext(evalCtx openfeature.FlattenedContext) (Evaluatio
 		return EvaluationContext{}, ErrMissingTargetKey
 	}
 
-	// Extract user data from the context
-	userData, ok := evalCtx["user"].(map[string]interface{})
-	if !ok {
-		userData = make(map[string]interface{})
+	config := p.settings()
+	mapper := config.ContextMapper
+	if mapper == nil {
+		mapper = DefaultContextMapper()
 	}
 
-	// Create User struct with data from the nested structure
-	user := &User{
-		ID:    getString(userData, "id", targetingKey),
-		Email: getString(userData, "email", ""),
-		Name:  getString(userData, "name", ""),
+	ctx, err := mapper.MapContext(evalCtx)
+	if err != nil {
+		return EvaluationContext{}, err
 	}
 
-	// Handle user custom attributes
-	if customAttrs, ok := userData["customAttributes"].(map[string]interface{}); ok {
-		user.CustomAttributes = customAttrs
-	} else {
-		user.CustomAttributes = make(map[string]interface{})
+	ctx.TargetingKey = targetingKey
+	ctx.Application = config.Application
+	ctx.Environment = config.Environment
+	if ctx.User == nil {
+		ctx.User = &User{}
 	}
-
-	// Build the evaluation context
-	ctx := EvaluationContext{
-		TargetingKey:     targetingKey,
-		Application:      p.config.Application,
-		Environment:      p.config.Environment,
-		User:             user,
-		CustomAttributes: make(map[string]interface{}),
+	if ctx.User.ID == "" {
+		ctx.User.ID = targetingKey
 	}
 
-	// Add remaining top-level attributes to customAttributes
-	for k, v := range evalCtx {
-		switch k {
-		case "targetingKey", "user":
-			continue
-		default:
-			ctx.CustomAttributes[k] = v
+	if config.ContextSchema != nil {
+		if err := config.ContextSchema.check(ctx); err != nil {
+			return EvaluationContext{}, err
 		}
 	}
 
@@ -382,17 +199,6 @@ func getString(m map[string]interface{}, key string, defaultValue string) string
 	return defaultValue
 }
 
-// Helper function to determine if an attribute belongs to user
-func isUserAttribute(key string) bool {
-	userAttributes := map[string]bool{
-		"role":    true,
-		"group":   true,
-		"company": true,
-		// Add other user-specific attributes as needed
-	}
-	return userAttributes[key]
-}
-
 func (p *Provider) Hooks() []openfeature.Hook {
 	return p.hooks
 }
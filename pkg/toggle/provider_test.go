@@ -11,8 +11,9 @@ import (
 
 // MockClient implements ClientInterface for testing
 type MockClient struct {
-	EvaluateFunc      func(ctx EvaluationContext) (*Response, error)
-	SendTelemetryFunc func(payload TelemetryPayload) error
+	EvaluateFunc          func(ctx EvaluationContext) (*Response, error)
+	SendTelemetryFunc     func(payload TelemetryPayload) error
+	SendTrackingEventFunc func(payload TrackingPayload) error
 }
 
 func (m *MockClient) Evaluate(ctx EvaluationContext) (*Response, error) {
@@ -29,6 +30,13 @@ func (m *MockClient) SendTelemetry(payload TelemetryPayload) error {
 	return nil
 }
 
+func (m *MockClient) SendTrackingEvent(payload TrackingPayload) error {
+	if m.SendTrackingEventFunc != nil {
+		return m.SendTrackingEventFunc(payload)
+	}
+	return nil
+}
+
 func TestNewProvider(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -137,7 +145,8 @@ func TestProvider_BooleanEvaluation(t *testing.T) {
 			expected: openfeature.BoolResolutionDetail{
 				Value: true,
 				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-					Reason: openfeature.TargetingMatchReason,
+					Reason:       openfeature.TargetingMatchReason,
+					FlagMetadata: openfeature.FlagMetadata{MetadataKeyCached: false},
 				},
 			},
 		},
@@ -160,7 +169,129 @@ func TestProvider_BooleanEvaluation(t *testing.T) {
 			expected: openfeature.BoolResolutionDetail{
 				Value: true,
 				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-					Reason: openfeature.TargetingMatchReason,
+					Reason:       openfeature.DefaultReason,
+					FlagMetadata: openfeature.FlagMetadata{MetadataKeyCached: false},
+				},
+			},
+		},
+		{
+			name:         "variant and metadata",
+			flag:         "test-flag",
+			defaultValue: false,
+			evalCtx: openfeature.FlattenedContext{
+				"targetingKey": "user-123",
+			},
+			mockResponse: &Response{
+				Toggles: map[string]Evaluation{
+					"test-flag": {
+						Type:    "boolean",
+						Value:   true,
+						Reason:  "targeting_match",
+						Variant: "treatment",
+						Version: "7",
+						RuleID:  "rule-1",
+					},
+				},
+				Cached: true,
+			},
+			expected: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.TargetingMatchReason,
+					Variant: "treatment",
+					FlagMetadata: openfeature.FlagMetadata{
+						MetadataKeyVersion: "7",
+						MetadataKeyRuleID:  "rule-1",
+						MetadataKeyCached:  true,
+					},
+				},
+			},
+		},
+		{
+			name:         "flag not found",
+			flag:         "missing-flag",
+			defaultValue: false,
+			evalCtx: openfeature.FlattenedContext{
+				"targetingKey": "user-123",
+			},
+			mockResponse: &Response{
+				Toggles: map[string]Evaluation{},
+			},
+			expected: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:          openfeature.ErrorReason,
+					ResolutionError: openfeature.NewFlagNotFoundResolutionError(ErrFlagNotFound.Error()),
+				},
+			},
+		},
+		{
+			name:         "type mismatch",
+			flag:         "test-flag",
+			defaultValue: false,
+			evalCtx: openfeature.FlattenedContext{
+				"targetingKey": "user-123",
+			},
+			mockResponse: &Response{
+				Toggles: map[string]Evaluation{
+					"test-flag": {
+						Type:  "string",
+						Value: "on",
+					},
+				},
+			},
+			expected: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:          openfeature.ErrorReason,
+					ResolutionError: openfeature.NewTypeMismatchResolutionError("invalid flag type"),
+				},
+			},
+		},
+		{
+			name:         "server-side evaluation error",
+			flag:         "test-flag",
+			defaultValue: false,
+			evalCtx: openfeature.FlattenedContext{
+				"targetingKey": "user-123",
+			},
+			mockResponse: &Response{
+				Toggles: map[string]Evaluation{
+					"test-flag": {
+						Type:  "boolean",
+						Error: "invalid context",
+					},
+				},
+			},
+			expected: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:          openfeature.ErrorReason,
+					ResolutionError: openfeature.NewInvalidContextResolutionError("flag test-flag: invalid context"),
+				},
+			},
+		},
+		{
+			name:         "split reason",
+			flag:         "test-flag",
+			defaultValue: false,
+			evalCtx: openfeature.FlattenedContext{
+				"targetingKey": "user-123",
+			},
+			mockResponse: &Response{
+				Toggles: map[string]Evaluation{
+					"test-flag": {
+						Type:   "boolean",
+						Value:  true,
+						Reason: "split",
+					},
+				},
+			},
+			expected: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       openfeature.SplitReason,
+					FlagMetadata: openfeature.FlagMetadata{MetadataKeyCached: false},
 				},
 			},
 		},
@@ -232,7 +363,8 @@ func TestProvider_StringEvaluation(t *testing.T) {
 			expected: openfeature.StringResolutionDetail{
 				Value: "test-value",
 				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-					Reason: openfeature.TargetingMatchReason,
+					Reason:       openfeature.TargetingMatchReason,
+					FlagMetadata: openfeature.FlagMetadata{MetadataKeyCached: false},
 				},
 			},
 		},
@@ -255,7 +387,8 @@ func TestProvider_StringEvaluation(t *testing.T) {
 			expected: openfeature.StringResolutionDetail{
 				Value: "test-value",
 				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-					Reason: openfeature.TargetingMatchReason,
+					Reason:       openfeature.DefaultReason,
+					FlagMetadata: openfeature.FlagMetadata{MetadataKeyCached: false},
 				},
 			},
 		},
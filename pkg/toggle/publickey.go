@@ -0,0 +1,108 @@
+package toggle
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// KeyKind identifies the kind of a Hyphen API key from its prefix.
+type KeyKind string
+
+const (
+	// KeyKindPublic is a key with the "public_" prefix, safe to embed in clients.
+	KeyKindPublic KeyKind = "public"
+	// KeyKindUnprefixed is a bare key without a kind prefix.
+	KeyKindUnprefixed KeyKind = "unprefixed"
+)
+
+// PublicKey is a parsed Hyphen public key. Its String form is redacted, so it
+// is safe to log.
+type PublicKey struct {
+	Kind      KeyKind
+	OrgID     string
+	ProjectID string
+
+	suffix string
+}
+
+// ParsePublicKey decodes a Hyphen public key of the form
+// "public_" + base64("<org id>:<project id>:<secret>"). Errors wrap ErrInvalidPublicKey.
+func ParsePublicKey(key string) (PublicKey, error) {
+	if key == "" {
+		return PublicKey{}, ErrMissingPublicKey
+	}
+
+	parsed := PublicKey{Kind: KeyKindUnprefixed, suffix: redactSuffix(key)}
+	body := key
+	// Standard base64 never contains "_", so anything before one is a kind prefix.
+	if prefix, rest, ok := strings.Cut(key, "_"); ok {
+		if KeyKind(prefix) != KeyKindPublic {
+			return PublicKey{}, fmt.Errorf("%w: unsupported key kind %q", ErrInvalidPublicKey, prefix)
+		}
+		parsed.Kind = KeyKindPublic
+		body = rest
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("%w: failed to decode public key: %v", ErrInvalidPublicKey, err)
+	}
+
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) < 2 {
+		return PublicKey{}, fmt.Errorf("%w: invalid key format: insufficient parts", ErrInvalidPublicKey)
+	}
+
+	parsed.OrgID = parts[0]
+	if !orgIDRegex.MatchString(parsed.OrgID) {
+		return PublicKey{}, fmt.Errorf("%w: invalid orgID format", ErrInvalidPublicKey)
+	}
+	parsed.ProjectID = parts[1]
+	if !orgIDRegex.MatchString(parsed.ProjectID) {
+		return PublicKey{}, fmt.Errorf("%w: invalid project ID format", ErrInvalidPublicKey)
+	}
+
+	return parsed, nil
+}
+
+// extractOrgID reads the organization ID from a public key. Unlike ParsePublicKey
+// it ignores the project ID and strips only a "public_" prefix, so keys accepted
+// before strict validation existed keep routing to their organization's Horizon.
+func extractOrgID(publicKey string) (string, error) {
+	key := strings.TrimPrefix(publicKey, "public_")
+
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode public key: %w", err)
+	}
+
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) < 2 {
+		return "", fmt.Errorf("invalid key format: insufficient parts")
+	}
+
+	orgID := parts[0]
+	if !orgIDRegex.MatchString(orgID) {
+		return "", fmt.Errorf("invalid orgID format")
+	}
+
+	return orgID, nil
+}
+
+// String returns a redacted description such as "public_…XyZ= (org: acme, project: web)".
+func (k PublicKey) String() string {
+	prefix := ""
+	if k.Kind == KeyKindPublic {
+		prefix = "public_"
+	}
+	return fmt.Sprintf("%s…%s (org: %s, project: %s)", prefix, k.suffix, k.OrgID, k.ProjectID)
+}
+
+// redactSuffix keeps the last four characters of long keys so they can be told apart in logs.
+func redactSuffix(key string) string {
+	if len(key) < 16 {
+		return ""
+	}
+	return key[len(key)-4:]
+}
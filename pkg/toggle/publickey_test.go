@@ -0,0 +1,144 @@
+package toggle
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePublicKey(t *testing.T) {
+	encode := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name    string
+		key     string
+		want    PublicKey
+		wantErr error
+	}{
+		{
+			name: "public key",
+			key:  "public_" + encode("test-org:proj_1:random"),
+			want: PublicKey{Kind: KeyKindPublic, OrgID: "test-org", ProjectID: "proj_1"},
+		},
+		{
+			name: "unprefixed key",
+			key:  encode("test-org:proj:random"),
+			want: PublicKey{Kind: KeyKindUnprefixed, OrgID: "test-org", ProjectID: "proj"},
+		},
+		{
+			name:    "empty",
+			key:     "",
+			wantErr: ErrMissingPublicKey,
+		},
+		{
+			name:    "unsupported kind",
+			key:     "secret_" + encode("test-org:proj:random"),
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name:    "invalid base64",
+			key:     "public_invalid_base64",
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name:    "missing project",
+			key:     "public_" + encode("test-org"),
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name:    "invalid org",
+			key:     "public_" + encode("test org:proj:random"),
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name:    "invalid project",
+			key:     "public_" + encode("test-org:proj/1:random"),
+			wantErr: ErrInvalidPublicKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePublicKey(tt.key)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.Kind, got.Kind)
+			assert.Equal(t, tt.want.OrgID, got.OrgID)
+			assert.Equal(t, tt.want.ProjectID, got.ProjectID)
+		})
+	}
+}
+
+func TestPublicKey_String(t *testing.T) {
+	raw := "public_" + base64.StdEncoding.EncodeToString([]byte("test-org:proj:super-secret-value"))
+	key, err := ParsePublicKey(raw)
+	assert.NoError(t, err)
+
+	for _, s := range []string{key.String(), fmt.Sprint(key), fmt.Sprintf("%+v", key)} {
+		assert.Equal(t, "public_…"+raw[len(raw)-4:]+" (org: test-org, project: proj)", s)
+		assert.False(t, strings.Contains(s, raw[len("public_"):len(raw)-4]))
+	}
+}
+
+func TestNewProvider_StrictPublicKey(t *testing.T) {
+	config := Config{
+		PublicKey:       "public_invalid_base64",
+		StrictPublicKey: true,
+		Application:     "test-app",
+		Environment:     "test-env",
+	}
+
+	_, err := NewProvider(config)
+	assert.ErrorIs(t, err, ErrInvalidPublicKey)
+
+	config.PublicKey = testPublicKey
+	p, err := NewProvider(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://org123.toggle.hyphen.cloud/toggle/evaluate", p.endpoints[0].Evaluate)
+}
+
+func TestHorizonURLs(t *testing.T) {
+	tests := []struct {
+		name      string
+		publicKey string
+		want      string
+	}{
+		{
+			name:      "valid key",
+			publicKey: testPublicKey,
+			want:      "https://org123.toggle.hyphen.cloud",
+		},
+		{
+			name:      "project ID that strict parsing rejects",
+			publicKey: "public_" + base64.StdEncoding.EncodeToString([]byte("org123:my project:secret")),
+			want:      "https://org123.toggle.hyphen.cloud",
+		},
+		{
+			name:      "unprefixed key",
+			publicKey: base64.StdEncoding.EncodeToString([]byte("org123:project:secret")),
+			want:      "https://org123.toggle.hyphen.cloud",
+		},
+		{
+			name:      "unsupported prefix",
+			publicKey: "secret_" + base64.StdEncoding.EncodeToString([]byte("org123:project:secret")),
+			want:      "https://toggle.hyphen.cloud",
+		},
+		{
+			name:      "undecodable key",
+			publicKey: "public_invalid_base64",
+			want:      "https://toggle.hyphen.cloud",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, []string{tt.want}, horizonURLs(Config{PublicKey: tt.publicKey}))
+		})
+	}
+}
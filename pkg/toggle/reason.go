@@ -0,0 +1,115 @@
+package toggle
+
+import (
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// horizonReasons maps normalized Horizon evaluation reasons to OpenFeature reasons.
+var horizonReasons = map[string]openfeature.Reason{
+	"static":            openfeature.StaticReason,
+	"default":           openfeature.DefaultReason,
+	"no_target_matched": openfeature.DefaultReason,
+	"targeting_match":   openfeature.TargetingMatchReason,
+	"target_matched":    openfeature.TargetingMatchReason,
+	"split":             openfeature.SplitReason,
+	"cached":            openfeature.CachedReason,
+	"disabled":          openfeature.DisabledReason,
+	"error":             openfeature.ErrorReason,
+}
+
+// horizonErrors maps normalized Horizon evaluation errors to OpenFeature error codes.
+var horizonErrors = map[string]openfeature.ErrorCode{
+	"flag_not_found":        openfeature.FlagNotFoundCode,
+	"toggle_not_found":      openfeature.FlagNotFoundCode,
+	"type_mismatch":         openfeature.TypeMismatchCode,
+	"parse_error":           openfeature.ParseErrorCode,
+	"targeting_key_missing": openfeature.TargetingKeyMissingCode,
+	"invalid_context":       openfeature.InvalidContextCode,
+	"provider_not_ready":    openfeature.ProviderNotReadyCode,
+}
+
+// normalizeCode lowercases a Horizon reason or error and replaces spaces and
+// hyphens with underscores, so "No target matched" and "NO_TARGET_MATCHED" compare equal.
+func normalizeCode(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(s)
+}
+
+// toReason translates a Horizon evaluation reason into an OpenFeature reason.
+// An empty reason is treated as a targeting match for compatibility with older
+// Horizon versions that did not report one.
+func toReason(reason string) openfeature.Reason {
+	if reason == "" {
+		return openfeature.TargetingMatchReason
+	}
+	if r, ok := horizonReasons[normalizeCode(reason)]; ok {
+		return r
+	}
+	return openfeature.UnknownReason
+}
+
+// toResolutionError translates a Horizon evaluation error into an OpenFeature resolution error.
+func toResolutionError(flag string, horizonErr string) openfeature.ResolutionError {
+	msg := "flag " + flag + ": " + horizonErr
+	switch horizonErrors[normalizeCode(horizonErr)] {
+	case openfeature.FlagNotFoundCode:
+		return openfeature.NewFlagNotFoundResolutionError(msg)
+	case openfeature.TypeMismatchCode:
+		return openfeature.NewTypeMismatchResolutionError(msg)
+	case openfeature.ParseErrorCode:
+		return openfeature.NewParseErrorResolutionError(msg)
+	case openfeature.TargetingKeyMissingCode:
+		return openfeature.NewTargetingKeyMissingResolutionError(msg)
+	case openfeature.InvalidContextCode:
+		return openfeature.NewInvalidContextResolutionError(msg)
+	case openfeature.ProviderNotReadyCode:
+		return openfeature.NewProviderNotReadyResolutionError(msg)
+	default:
+		return openfeature.NewGeneralResolutionError(msg)
+	}
+}
+
+// Keys set on the FlagMetadata of a successful resolution.
+const (
+	MetadataKeyVersion = "version"
+	MetadataKeyRuleID  = "ruleId"
+	MetadataKeyCached  = "cached"
+)
+
+// resolutionDetail builds the successful resolution detail for toggle, carrying the
+// Horizon variant, version, rule and cache state through to the OpenFeature client.
+func resolutionDetail(resp *Response, toggle Evaluation) openfeature.ProviderResolutionDetail {
+	metadata := openfeature.FlagMetadata{
+		MetadataKeyCached: toggle.Cached || resp.Cached,
+	}
+	if toggle.Version != "" {
+		metadata[MetadataKeyVersion] = toggle.Version
+	}
+	if toggle.RuleID != "" {
+		metadata[MetadataKeyRuleID] = toggle.RuleID
+	}
+
+	return openfeature.ProviderResolutionDetail{
+		Reason:       toReason(toggle.Reason),
+		Variant:      toggle.Variant,
+		FlagMetadata: metadata,
+	}
+}
+
+// lookupToggle finds flag in the Horizon response. It returns false together with
+// the resolution error to report when the flag is absent or Horizon failed to evaluate it.
+func lookupToggle(resp *Response, flag string) (Evaluation, openfeature.ResolutionError, bool) {
+	if resp == nil {
+		return Evaluation{}, openfeature.NewFlagNotFoundResolutionError(ErrFlagNotFound.Error()), false
+	}
+	toggle, ok := resp.Toggles[flag]
+	if !ok {
+		return Evaluation{}, openfeature.NewFlagNotFoundResolutionError(ErrFlagNotFound.Error()), false
+	}
+	if toggle.Error != "" {
+		return toggle, toResolutionError(flag, toggle.Error), false
+	}
+	return toggle, openfeature.ResolutionError{}, true
+}
@@ -0,0 +1,81 @@
+package toggle
+
+import (
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToReason(t *testing.T) {
+	tests := []struct {
+		reason string
+		want   openfeature.Reason
+	}{
+		{reason: "", want: openfeature.TargetingMatchReason},
+		{reason: "static", want: openfeature.StaticReason},
+		{reason: "DEFAULT", want: openfeature.DefaultReason},
+		{reason: "no target matched", want: openfeature.DefaultReason},
+		{reason: "targeting_match", want: openfeature.TargetingMatchReason},
+		{reason: "Targeting-Match", want: openfeature.TargetingMatchReason},
+		{reason: "split", want: openfeature.SplitReason},
+		{reason: "cached", want: openfeature.CachedReason},
+		{reason: "disabled", want: openfeature.DisabledReason},
+		{reason: "something new", want: openfeature.UnknownReason},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			assert.Equal(t, tt.want, toReason(tt.reason))
+		})
+	}
+}
+
+func TestToResolutionError(t *testing.T) {
+	tests := []struct {
+		horizonErr string
+		want       openfeature.ErrorCode
+	}{
+		{horizonErr: "flag not found", want: openfeature.FlagNotFoundCode},
+		{horizonErr: "TOGGLE_NOT_FOUND", want: openfeature.FlagNotFoundCode},
+		{horizonErr: "type mismatch", want: openfeature.TypeMismatchCode},
+		{horizonErr: "parse_error", want: openfeature.ParseErrorCode},
+		{horizonErr: "targeting key missing", want: openfeature.TargetingKeyMissingCode},
+		{horizonErr: "invalid-context", want: openfeature.InvalidContextCode},
+		{horizonErr: "internal failure", want: openfeature.GeneralCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.horizonErr, func(t *testing.T) {
+			detail := openfeature.ProviderResolutionDetail{
+				ResolutionError: toResolutionError("test-flag", tt.horizonErr),
+			}.ResolutionDetail()
+			assert.Equal(t, tt.want, detail.ErrorCode)
+			assert.Contains(t, detail.ErrorMessage, tt.horizonErr)
+		})
+	}
+}
+
+func TestLookupToggle(t *testing.T) {
+	resp := &Response{
+		Toggles: map[string]Evaluation{
+			"ok":     {Key: "ok", Type: "boolean", Value: true},
+			"failed": {Key: "failed", Type: "boolean", Error: "flag not found"},
+		},
+	}
+
+	toggle, _, ok := lookupToggle(resp, "ok")
+	assert.True(t, ok)
+	assert.Equal(t, true, toggle.Value)
+
+	_, resErr, ok := lookupToggle(resp, "missing")
+	assert.False(t, ok)
+	assert.Equal(t, openfeature.NewFlagNotFoundResolutionError(ErrFlagNotFound.Error()), resErr)
+
+	_, resErr, ok = lookupToggle(resp, "failed")
+	assert.False(t, ok)
+	assert.Equal(t, openfeature.NewFlagNotFoundResolutionError("flag failed: flag not found"), resErr)
+
+	_, _, ok = lookupToggle(nil, "ok")
+	assert.False(t, ok)
+}
@@ -0,0 +1,200 @@
+package toggle
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// UpdateConfig replaces the provider's configuration without re-registering it.
+// Horizon URLs, the public key, cache and telemetry settings take effect for the
+// next evaluation; evaluations already in flight finish with the previous settings.
+// Cached evaluations are kept unless the public key, application or environment
+// change. The config must pass ValidateConfig, otherwise the current one is kept.
+// A ProviderConfigChange event is emitted once the new config is active.
+func (p *Provider) UpdateConfig(config Config) error {
+	if err := ValidateConfig(config); err != nil {
+		return err
+	}
+	endpoints := newEndpoints(horizonURLs(config))
+
+	p.mu.Lock()
+	sinks, err := p.sinks.reconfigure(config)
+	if err != nil {
+		p.mu.Unlock()
+		return err
+	}
+	replaced := p.sinks
+	p.sinks = sinks
+	previous := p.config
+	resetCache := previous.PublicKey != config.PublicKey ||
+		previous.Application != config.Application ||
+		previous.Environment != config.Environment
+	if client, ok := p.client.(*Client); ok {
+		client.reconfigure(config, endpoints, resetCache)
+	}
+	p.config = config
+	p.endpoints = endpoints
+	if !reflect.DeepEqual(previous.Exposures, config.Exposures) ||
+		!reflect.DeepEqual(previous.TelemetrySinks, config.TelemetrySinks) ||
+		replaced != sinks {
+		p.exposures = p.newExposures(config, sinks)
+	}
+	p.mu.Unlock()
+	if err := replaced.closeReplaced(sinks); err != nil {
+		logError(context.Background(), "Error closing telemetry file:", err)
+	}
+	p.setConfiguredOverrides(config.Overrides)
+
+	p.emit(openfeature.ProviderConfigChange, openfeature.ProviderEventDetails{
+		Message: "provider configuration updated",
+	})
+	return nil
+}
+
+// EventChannel delivers ProviderConfigChange events emitted by UpdateConfig.
+func (p *Provider) EventChannel() <-chan openfeature.Event {
+	return p.events
+}
+
+func (p *Provider) emit(eventType openfeature.EventType, details openfeature.ProviderEventDetails) {
+	if p.events == nil {
+		return
+	}
+	select {
+	case p.events <- openfeature.Event{
+		ProviderName:         p.Metadata().Name,
+		EventType:            eventType,
+		ProviderEventDetails: details,
+	}:
+	default:
+	}
+}
+
+// WatchConfigFile polls the configuration file at path every interval in the
+// background and applies it with UpdateConfig whenever its content changes after
+// the call. Only the settings a file can express are replaced, see reloadedConfig.
+// Watching stops when ctx is done. Load, validation and update failures are
+// reported through the logger stored under the "logger" context key, and the
+// current config is kept.
+func (p *Provider) WatchConfigFile(ctx context.Context, path string, interval time.Duration) {
+	p.watchConfig(ctx, interval, func() ([]byte, error) {
+		return os.ReadFile(path)
+	}, func() (Config, error) {
+		return LoadConfig(path)
+	})
+}
+
+// WatchConfigEnv polls the HYPHEN_* environment variables every interval in the
+// background and applies them with UpdateConfig whenever they change after the call.
+// Watching stops when ctx is done, and failures are reported like WatchConfigFile.
+func (p *Provider) WatchConfigEnv(ctx context.Context, interval time.Duration) {
+	p.watchConfig(ctx, interval, func() ([]byte, error) {
+		var vars []string
+		for _, kv := range os.Environ() {
+			if strings.HasPrefix(kv, "HYPHEN_") {
+				vars = append(vars, kv)
+			}
+		}
+		sort.Strings(vars)
+		return []byte(strings.Join(vars, "\n")), nil
+	}, ConfigFromEnv)
+}
+
+// watchConfig reloads the config with load whenever the fingerprint of its source
+// changes. The initial fingerprint is taken before it returns.
+func (p *Provider) watchConfig(ctx context.Context, interval time.Duration, fingerprint func() ([]byte, error), load func() (Config, error)) {
+	last, _ := fingerprint()
+	go p.pollConfig(ctx, interval, last, fingerprint, load)
+}
+
+func (p *Provider) pollConfig(ctx context.Context, interval time.Duration, last []byte, fingerprint func() ([]byte, error), load func() (Config, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current, err := fingerprint()
+			if err != nil {
+				logError(ctx, "Error reading provider configuration:", err)
+				continue
+			}
+			if bytes.Equal(current, last) {
+				continue
+			}
+			last = current
+
+			config, err := load()
+			if err != nil {
+				logError(ctx, "Error loading provider configuration:", err)
+				continue
+			}
+			if err := p.UpdateConfig(reloadedConfig(p.settings(), config)); err != nil {
+				logError(ctx, "Error updating provider configuration:", err)
+			}
+		}
+	}
+}
+
+// reloadedConfig applies the settings loaded from a file or the environment to
+// current. Settings only code can provide are kept: the context mapper and schema,
+// TelemetrySinks, the cache key generator, exposure sinks, and the anonymous key
+// strategy unless the source sets a different one. Keeping a strategy loaded from
+// the same setting keeps its state, such as the key of ProcessAnonymousKey.
+func reloadedConfig(current, loaded Config) Config {
+	next := current
+	next.PublicKey = loaded.PublicKey
+	next.StrictPublicKey = loaded.StrictPublicKey
+	next.Application = loaded.Application
+	next.Environment = loaded.Environment
+	next.HorizonUrls = loaded.HorizonUrls
+	next.EnableUsage = loaded.EnableUsage
+	next.IntRounding = loaded.IntRounding
+	next.TelemetryFile = loaded.TelemetryFile
+	next.TelemetryWebhook = loaded.TelemetryWebhook
+	next.TelemetryWebhookHeaders = loaded.TelemetryWebhookHeaders
+	next.Overrides = loaded.Overrides
+	next.AllowRequestOverrides = loaded.AllowRequestOverrides
+	next.RequestOverrideEnvironments = loaded.RequestOverrideEnvironments
+	if loaded.AnonymousKey != nil && !sameAnonymousKeySetting(current.AnonymousKey, loaded.AnonymousKey) {
+		next.AnonymousKey = loaded.AnonymousKey
+	}
+
+	next.Cache = nil
+	if loaded.Cache != nil {
+		cache := *loaded.Cache
+		if current.Cache != nil && cache.KeyGen == nil {
+			cache.KeyGen = current.Cache.KeyGen
+		}
+		next.Cache = &cache
+	}
+
+	next.Exposures = nil
+	if loaded.Exposures != nil {
+		exposures := *loaded.Exposures
+		if current.Exposures != nil && exposures.Sinks == nil {
+			exposures.Sinks = current.Exposures.Sinks
+		}
+		next.Exposures = &exposures
+	}
+	return next
+}
+
+// sameAnonymousKeySetting reports whether both strategies were loaded from the same setting.
+func sameAnonymousKeySetting(current, loaded AnonymousKeyStrategy) bool {
+	c, ok := current.(loadedAnonymousKey)
+	if !ok {
+		return false
+	}
+	l, ok := loaded.(loadedAnonymousKey)
+	return ok && c.setting == l.setting
+}
@@ -0,0 +1,316 @@
+package toggle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+// newHorizonServer returns a Horizon stub that resolves "test-flag" to value and
+// counts the evaluations it serves.
+func newHorizonServer(t *testing.T, value string) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_ = json.NewEncoder(w).Encode(Response{Toggles: map[string]Evaluation{
+			"test-flag": {Key: "test-flag", Type: "string", Value: value},
+		}})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func reloadConfig(url string) Config {
+	enableUsage := false
+	return Config{
+		PublicKey:   testPublicKey,
+		Application: "test-app",
+		Environment: "test-env",
+		HorizonUrls: []string{url},
+		EnableUsage: &enableUsage,
+		Cache: &CacheConfig{
+			TTL:    time.Minute,
+			KeyGen: func(ctx EvaluationContext) string { return ctx.TargetingKey },
+		},
+	}
+}
+
+func TestProvider_UpdateConfig(t *testing.T) {
+	first, firstCalls := newHorizonServer(t, "first")
+	second, secondCalls := newHorizonServer(t, "second")
+
+	p, err := NewProvider(reloadConfig(first.URL))
+	assert.NoError(t, err)
+	evaluate := func(key string) openfeature.StringResolutionDetail {
+		return p.StringEvaluation(context.Background(), "test-flag", "default", openfeature.FlattenedContext{"targetingKey": key})
+	}
+
+	assert.Equal(t, "first", evaluate("user-1").Value)
+	assert.Equal(t, int32(1), atomic.LoadInt32(firstCalls))
+
+	// Switching endpoints and TTL keeps cached evaluations.
+	config := reloadConfig(second.URL + "/")
+	config.Cache.TTL = 2 * time.Minute
+	assert.NoError(t, p.UpdateConfig(config))
+
+	event := <-p.EventChannel()
+	assert.Equal(t, openfeature.ProviderConfigChange, event.EventType)
+
+	cached := evaluate("user-1")
+	assert.Equal(t, "first", cached.Value)
+	assert.True(t, cached.FlagMetadata[MetadataKeyCached].(bool))
+	assert.Equal(t, "second", evaluate("user-2").Value)
+	assert.Equal(t, int32(1), atomic.LoadInt32(firstCalls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(secondCalls))
+	assert.Equal(t, second.URL+"/toggle/evaluate", p.endpoints[0].Evaluate)
+
+	// Changing the environment resets the cache.
+	config = reloadConfig(second.URL)
+	config.Environment = "other-env"
+	assert.NoError(t, p.UpdateConfig(config))
+	assert.Equal(t, "second", evaluate("user-1").Value)
+	assert.Equal(t, int32(2), atomic.LoadInt32(secondCalls))
+
+	// Disabling the cache.
+	config.Cache = nil
+	assert.NoError(t, p.UpdateConfig(config))
+	evaluate("user-1")
+	evaluate("user-1")
+	assert.Equal(t, int32(4), atomic.LoadInt32(secondCalls))
+}
+
+func TestProvider_UpdateConfigInvalid(t *testing.T) {
+	server, _ := newHorizonServer(t, "value")
+	p, err := NewProvider(reloadConfig(server.URL))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr error
+	}{
+		{
+			name:    "missing application",
+			modify:  func(c *Config) { c.Application = "" },
+			wantErr: ErrMissingApplication,
+		},
+		{
+			name:    "zero cache TTL",
+			modify:  func(c *Config) { c.Cache.TTL = 0 },
+			wantErr: ErrInvalidCacheTTL,
+		},
+		{
+			name:    "negative cache TTL",
+			modify:  func(c *Config) { c.Cache.TTL = -time.Minute },
+			wantErr: ErrInvalidCacheTTL,
+		},
+		{
+			name:    "invalid Horizon URL",
+			modify:  func(c *Config) { c.HorizonUrls = []string{"not a url"} },
+			wantErr: ErrInvalidHorizonURL,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := reloadConfig(server.URL)
+			tt.modify(&config)
+			assert.ErrorIs(t, p.UpdateConfig(config), tt.wantErr)
+			assert.Equal(t, reloadConfig(server.URL).HorizonUrls, p.settings().HorizonUrls)
+			assert.Equal(t, time.Minute, p.settings().Cache.TTL)
+			assert.Equal(t, "test-app", p.settings().Application)
+		})
+	}
+
+	select {
+	case event := <-p.EventChannel():
+		t.Fatalf("unexpected event %v", event.EventType)
+	default:
+	}
+}
+
+func TestProvider_UpdateConfigConcurrent(t *testing.T) {
+	first, _ := newHorizonServer(t, "first")
+	second, _ := newHorizonServer(t, "second")
+	p, err := NewProvider(reloadConfig(first.URL))
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				result := p.StringEvaluation(context.Background(), "test-flag", "default", openfeature.FlattenedContext{"targetingKey": "user"})
+				assert.Contains(t, []string{"first", "second"}, result.Value)
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		url := first.URL
+		if i%2 == 1 {
+			url = second.URL
+		}
+		assert.NoError(t, p.UpdateConfig(reloadConfig(url)))
+	}
+	wg.Wait()
+}
+
+func TestProvider_WatchConfigFile(t *testing.T) {
+	server, _ := newHorizonServer(t, "value")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(environment string) {
+		content := "publicKey: " + testPublicKey + "\napplication: test-app\nenvironment: " + environment + "\nhorizonUrls: [" + server.URL + "]\n"
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	write("test-env")
+
+	config, err := LoadConfig(path)
+	assert.NoError(t, err)
+	p, err := NewProvider(config)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.WatchConfigFile(ctx, path, 10*time.Millisecond)
+
+	write("production")
+	select {
+	case event := <-p.EventChannel():
+		assert.Equal(t, openfeature.ProviderConfigChange, event.EventType)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config change was not applied")
+	}
+	assert.Equal(t, "production", p.settings().Environment)
+}
+
+func TestProvider_WatchConfigEnv(t *testing.T) {
+	server, _ := newHorizonServer(t, "value")
+	t.Setenv(EnvPublicKey, testPublicKey)
+	t.Setenv(EnvApplication, "test-app")
+	t.Setenv(EnvEnvironment, "test-env")
+	t.Setenv(EnvHorizonUrls, server.URL)
+
+	config, err := ConfigFromEnv()
+	assert.NoError(t, err)
+	p, err := NewProvider(config)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.WatchConfigEnv(ctx, 10*time.Millisecond)
+
+	t.Setenv(EnvCacheTTL, "1m")
+	select {
+	case <-p.EventChannel():
+	case <-time.After(5 * time.Second):
+		t.Fatal("config change was not applied")
+	}
+	assert.Equal(t, time.Minute, p.settings().Cache.TTL)
+}
+
+func TestProvider_WatchConfigFileKeepsCodeSettings(t *testing.T) {
+	server, _ := newHorizonServer(t, "value")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	telemetryPath := filepath.Join(dir, "telemetry.ndjson")
+	write := func(environment string) {
+		content := "publicKey: " + testPublicKey + "\napplication: test-app\nenvironment: " + environment +
+			"\nhorizonUrls: [" + server.URL + "]\ncache:\n  ttl: 1m\nexposures:\n  dedupWindow: 1h\ntelemetry:\n  file: " + telemetryPath + "\n"
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	write("test-env")
+
+	config, err := LoadConfig(path)
+	assert.NoError(t, err)
+	sink := NewMemorySink()
+	exposureSink := NewMemorySink()
+	mapper, err := NewAttributeMapper(MappingConfig{})
+	assert.NoError(t, err)
+	keyGen := func(ctx EvaluationContext) string { return ctx.TargetingKey }
+	config.TelemetrySinks = []TelemetrySink{sink}
+	config.ContextMapper = mapper
+	config.Cache.KeyGen = keyGen
+	config.Exposures.Sinks = []ExposureSink{exposureSink}
+	p, err := NewProvider(config)
+	assert.NoError(t, err)
+	defer p.Close()
+	fileSink := p.sinks.file
+	exposures := p.exposures
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.WatchConfigFile(ctx, path, 10*time.Millisecond)
+
+	write("staging")
+	select {
+	case <-p.EventChannel():
+	case <-time.After(5 * time.Second):
+		t.Fatal("config change was not applied")
+	}
+
+	settings := p.settings()
+	assert.Equal(t, "staging", settings.Environment)
+	assert.Equal(t, []TelemetrySink{sink}, settings.TelemetrySinks)
+	assert.Same(t, mapper, settings.ContextMapper)
+	assert.NotNil(t, settings.Cache.KeyGen)
+	assert.Equal(t, []ExposureSink{exposureSink}, settings.Exposures.Sinks)
+
+	// The telemetry file stays open and exposure deduplication is kept.
+	p.mu.RLock()
+	assert.Same(t, fileSink, p.sinks.file)
+	assert.Same(t, exposures, p.exposures)
+	p.mu.RUnlock()
+
+	// Reloads that fail ValidateConfig are not applied.
+	assert.NoError(t, os.WriteFile(path, []byte("publicKey: test-key\napplication: test-app\nenvironment: test-env\n"), 0o600))
+	select {
+	case event := <-p.EventChannel():
+		t.Fatalf("unexpected event %v", event.EventType)
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Equal(t, "staging", p.settings().Environment)
+}
+
+func TestReloadedConfig_AnonymousKey(t *testing.T) {
+	load := func(setting string) Config {
+		strategy, err := parseAnonymousKey(setting)
+		assert.NoError(t, err)
+		return Config{AnonymousKey: strategy}
+	}
+	attributes := map[string]interface{}{"application": "test-app", "environment": "test-env"}
+	key := func(config Config) string {
+		k, err := config.AnonymousKey.AnonymousKey(attributes)
+		assert.NoError(t, err)
+		return k
+	}
+
+	current := load("process")
+	processKey := key(current)
+
+	// Reloading the same setting keeps the strategy and its process-wide key.
+	next := reloadedConfig(current, load("process"))
+	assert.Equal(t, processKey, key(next))
+
+	// A source without a strategy keeps the current one.
+	next = reloadedConfig(current, Config{})
+	assert.Equal(t, processKey, key(next))
+
+	// A changed setting replaces it.
+	next = reloadedConfig(current, load("hashed:application"))
+	assert.NotEqual(t, processKey, key(next))
+
+	// A strategy set in code is replaced by a loaded one.
+	custom := Config{AnonymousKey: AnonymousKeyFunc(func(map[string]interface{}) (string, error) { return "custom", nil })}
+	next = reloadedConfig(custom, load("process"))
+	assert.NotEqual(t, "custom", key(next))
+}
@@ -0,0 +1,276 @@
+package toggle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Route is the Hyphen application and environment a flag is evaluated in.
+type Route struct {
+	Application string
+	Environment string
+}
+
+// RoutingConfig configures a RoutingProvider.
+type RoutingConfig struct {
+	// Config holds the settings shared by every route. Its Application and
+	// Environment, when both set, are the default route.
+	Config
+	// Routes lists the routes evaluations may use, in addition to the default
+	// route. Evaluations routed anywhere else resolve with INVALID_CONTEXT, so
+	// context attributes cannot create providers without bound.
+	Routes []Route
+	// Router selects the route for an evaluation. When nil, the "application" and
+	// "environment" attributes of the evaluation context are used, falling back to
+	// the default route.
+	Router func(evalCtx openfeature.FlattenedContext) (Route, error)
+}
+
+// RoutingProvider evaluates flags for several Hyphen applications and environments,
+// such as the tenants of a control plane. It keeps one Provider per allowed route,
+// created by NewRoutingProvider. Every route shares the HTTP client, and so its connection pool, and
+// the cache settings, but has its own cache, exposure dedup state and telemetry context.
+type RoutingProvider struct {
+	config     RoutingConfig
+	httpClient *http.Client
+	hooks      []openfeature.Hook
+	// providers is fixed once NewRoutingProvider returns, so reads need no lock.
+	providers map[Route]*Provider
+}
+
+func NewRoutingProvider(config RoutingConfig) (*RoutingProvider, error) {
+	if config.PublicKey == "" {
+		return nil, ErrMissingPublicKey
+	}
+	if config.Environment != "" {
+		if err := validateEnvironmentFormat(config.Environment); err != nil {
+			return nil, err
+		}
+	}
+
+	routes := config.Routes
+	if config.Application != "" && config.Environment != "" {
+		routes = append([]Route{{Application: config.Application, Environment: config.Environment}}, routes...)
+	}
+	if len(routes) == 0 {
+		return nil, ErrNoRoutes
+	}
+
+	r := &RoutingProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: time.Second * 10},
+		providers:  make(map[Route]*Provider, len(routes)),
+	}
+	for _, route := range routes {
+		if _, ok := r.providers[route]; ok {
+			continue
+		}
+		p, err := r.newProvider(route)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("route %s/%s: %w", route.Application, route.Environment, err)
+		}
+		r.providers[route] = p
+	}
+	r.hooks = []openfeature.Hook{&routingHook{router: r}}
+	return r, nil
+}
+
+func (r *RoutingProvider) newProvider(route Route) (*Provider, error) {
+	config := r.config.Config
+	config.Application = route.Application
+	config.Environment = route.Environment
+	p, err := NewProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	if client, ok := p.client.(*Client); ok {
+		client.httpClient = r.httpClient
+	}
+	return p, nil
+}
+
+// Close closes the provider of every route, see Provider.Close.
+func (r *RoutingProvider) Close() error {
+	var errs []error
+	for _, p := range r.providers {
+		if err := p.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *RoutingProvider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{
+		Name: "hyphen-routing-provider",
+	}
+}
+
+func (r *RoutingProvider) Hooks() []openfeature.Hook {
+	return r.hooks
+}
+
+// Provider returns the provider for route, or ErrUnknownRoute when the route is
+// not allowed. Register it for an OpenFeature domain to bind that domain to the route:
+//
+//	tenant, err := router.Provider(toggle.Route{Application: "billing", Environment: "production"})
+//	openfeature.SetNamedProvider("billing", tenant)
+func (r *RoutingProvider) Provider(route Route) (*Provider, error) {
+	p, ok := r.providers[route]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrUnknownRoute, route.Application, route.Environment)
+	}
+	return p, nil
+}
+
+// Bind registers the provider for route as the provider of the OpenFeature domain.
+func (r *RoutingProvider) Bind(domain string, route Route) error {
+	p, err := r.Provider(route)
+	if err != nil {
+		return err
+	}
+	return openfeature.SetNamedProvider(domain, p)
+}
+
+// route selects the route for evalCtx.
+func (r *RoutingProvider) route(evalCtx openfeature.FlattenedContext) (Route, error) {
+	if r.config.Router != nil {
+		return r.config.Router(evalCtx)
+	}
+
+	route := Route{Application: r.config.Application, Environment: r.config.Environment}
+	if application, ok := evalCtx["application"].(string); ok && application != "" {
+		route.Application = application
+	}
+	if environment, ok := evalCtx["environment"].(string); ok && environment != "" {
+		route.Environment = environment
+	}
+	return route, nil
+}
+
+// resolveProvider returns the provider routed to for evalCtx.
+func (r *RoutingProvider) resolveProvider(evalCtx openfeature.FlattenedContext) (*Provider, error) {
+	route, err := r.route(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return r.Provider(route)
+}
+
+func (r *RoutingProvider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	p, err := r.resolveProvider(evalCtx)
+	if err != nil {
+		return openfeature.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: routeErrorDetail(err)}
+	}
+	return p.BooleanEvaluation(ctx, flag, defaultValue, evalCtx)
+}
+
+func (r *RoutingProvider) StringEvaluation(ctx context.Context, flag string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	p, err := r.resolveProvider(evalCtx)
+	if err != nil {
+		return openfeature.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: routeErrorDetail(err)}
+	}
+	return p.StringEvaluation(ctx, flag, defaultValue, evalCtx)
+}
+
+func (r *RoutingProvider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	p, err := r.resolveProvider(evalCtx)
+	if err != nil {
+		return openfeature.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: routeErrorDetail(err)}
+	}
+	return p.FloatEvaluation(ctx, flag, defaultValue, evalCtx)
+}
+
+func (r *RoutingProvider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	p, err := r.resolveProvider(evalCtx)
+	if err != nil {
+		return openfeature.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: routeErrorDetail(err)}
+	}
+	return p.IntEvaluation(ctx, flag, defaultValue, evalCtx)
+}
+
+func (r *RoutingProvider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	p, err := r.resolveProvider(evalCtx)
+	if err != nil {
+		return openfeature.InterfaceResolutionDetail{Value: defaultValue, ProviderResolutionDetail: routeErrorDetail(err)}
+	}
+	return p.ObjectEvaluation(ctx, flag, defaultValue, evalCtx)
+}
+
+// Track records the event with the provider routed to for evalCtx.
+func (r *RoutingProvider) Track(ctx context.Context, trackingEventName string, evalCtx openfeature.EvaluationContext, details openfeature.TrackingEventDetails) {
+	p, err := r.resolveProvider(flattenEvaluationContext(evalCtx))
+	if err != nil {
+		logError(ctx, "Error tracking event:", err)
+		return
+	}
+	p.Track(ctx, trackingEventName, evalCtx, details)
+}
+
+// EvaluateAll resolves every flag of the route selected for evalCtx.
+func (r *RoutingProvider) EvaluateAll(ctx context.Context, evalCtx openfeature.EvaluationContext) (map[string]FlagResolution, error) {
+	p, err := r.resolveProvider(flattenEvaluationContext(mergeTransactionContext(ctx, evalCtx)))
+	if err != nil {
+		return nil, err
+	}
+	return p.EvaluateAll(ctx, evalCtx)
+}
+
+func routeErrorDetail(err error) openfeature.ProviderResolutionDetail {
+	return errorDetail(openfeature.NewInvalidContextResolutionError(err.Error()))
+}
+
+// routingHook runs the ProviderHook of the provider routed to for each evaluation.
+// ProviderHook.Before writes the route into the "application" and "environment"
+// attributes, so the evaluation and the later stages resolve the same route.
+type routingHook struct {
+	router *RoutingProvider
+}
+
+func (h *routingHook) hook(hookContext openfeature.HookContext) (*ProviderHook, error) {
+	p, err := h.router.resolveProvider(flattenEvaluationContext(hookContext.EvaluationContext()))
+	if err != nil {
+		return nil, err
+	}
+	return NewProviderHook(p), nil
+}
+
+func (h *routingHook) Before(ctx context.Context, hookContext openfeature.HookContext, hookHints openfeature.HookHints) (*openfeature.EvaluationContext, error) {
+	hook, err := h.hook(hookContext)
+	if err != nil {
+		return nil, err
+	}
+	return hook.Before(ctx, hookContext, hookHints)
+}
+
+func (h *routingHook) After(ctx context.Context, hookContext openfeature.HookContext, details openfeature.InterfaceEvaluationDetails, hookHints openfeature.HookHints) error {
+	hook, err := h.hook(hookContext)
+	if err != nil {
+		logError(ctx, "Error routing evaluation:", err)
+		return nil
+	}
+	return hook.After(ctx, hookContext, details, hookHints)
+}
+
+func (h *routingHook) Error(ctx context.Context, hookContext openfeature.HookContext, err error, hookHints openfeature.HookHints) {
+	hook, routeErr := h.hook(hookContext)
+	if routeErr != nil {
+		logError(ctx, "Error routing evaluation:", routeErr)
+		return
+	}
+	hook.Error(ctx, hookContext, err, hookHints)
+}
+
+func (h *routingHook) Finally(ctx context.Context, hookContext openfeature.HookContext, hookHints openfeature.HookHints) {
+	hook, err := h.hook(hookContext)
+	if err != nil {
+		return
+	}
+	hook.Finally(ctx, hookContext, hookHints)
+}
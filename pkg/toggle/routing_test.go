@@ -0,0 +1,192 @@
+package toggle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRoutingServer returns a Horizon stub that resolves "test-flag" to the
+// requested "application/environment" and counts the evaluations it serves.
+func newRoutingServer(t *testing.T) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		var evalCtx EvaluationContext
+		_ = json.NewDecoder(r.Body).Decode(&evalCtx)
+		_ = json.NewEncoder(w).Encode(Response{Toggles: map[string]Evaluation{
+			"test-flag": {Key: "test-flag", Type: "string", Value: evalCtx.Application + "/" + evalCtx.Environment},
+		}})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func routingConfig(url string) RoutingConfig {
+	enableUsage := false
+	return RoutingConfig{
+		Config: Config{
+			PublicKey:   "test-key",
+			Application: "default-app",
+			Environment: "production",
+			HorizonUrls: []string{url},
+			EnableUsage: &enableUsage,
+			Cache: &CacheConfig{
+				TTL:    time.Minute,
+				KeyGen: func(ctx EvaluationContext) string { return ctx.TargetingKey },
+			},
+		},
+		Routes: []Route{
+			{Application: "billing", Environment: "production"},
+			{Application: "billing", Environment: "staging"},
+			{Application: "search", Environment: "production"},
+			{Application: "acme", Environment: "production"},
+		},
+	}
+}
+
+func TestNewRoutingProvider(t *testing.T) {
+	_, err := NewRoutingProvider(RoutingConfig{})
+	assert.ErrorIs(t, err, ErrMissingPublicKey)
+
+	_, err = NewRoutingProvider(RoutingConfig{Config: Config{PublicKey: "test-key", Environment: "invalid env"}})
+	assert.ErrorIs(t, err, ErrInvalidEnvironmentFormat)
+
+	_, err = NewRoutingProvider(RoutingConfig{Config: Config{PublicKey: "test-key"}})
+	assert.ErrorIs(t, err, ErrNoRoutes)
+
+	_, err = NewRoutingProvider(RoutingConfig{
+		Config: Config{PublicKey: "test-key"},
+		Routes: []Route{{Application: "billing"}},
+	})
+	assert.ErrorIs(t, err, ErrMissingEnvironment)
+
+	r, err := NewRoutingProvider(RoutingConfig{
+		Config: Config{PublicKey: "test-key"},
+		Routes: []Route{{Application: "billing", Environment: "production"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "hyphen-routing-provider", r.Metadata().Name)
+	assert.Len(t, r.Hooks(), 1)
+	assert.NoError(t, r.Close())
+}
+
+func TestRoutingProvider_Evaluation(t *testing.T) {
+	server, calls := newRoutingServer(t)
+
+	tests := []struct {
+		name    string
+		evalCtx openfeature.FlattenedContext
+		want    string
+	}{
+		{
+			name:    "default route",
+			evalCtx: openfeature.FlattenedContext{"targetingKey": "user-1"},
+			want:    "default-app/production",
+		},
+		{
+			name:    "application from context",
+			evalCtx: openfeature.FlattenedContext{"targetingKey": "user-1", "application": "billing"},
+			want:    "billing/production",
+		},
+		{
+			name:    "application and environment from context",
+			evalCtx: openfeature.FlattenedContext{"targetingKey": "user-1", "application": "billing", "environment": "staging"},
+			want:    "billing/staging",
+		},
+	}
+
+	r, err := NewRoutingProvider(routingConfig(server.URL))
+	assert.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := r.StringEvaluation(context.Background(), "test-flag", "default", tt.evalCtx)
+			assert.Equal(t, tt.want, res.Value)
+			assert.Empty(t, res.ResolutionDetail().ErrorCode)
+		})
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+
+	// Each route keeps its own cache.
+	for _, tt := range tests {
+		res := r.StringEvaluation(context.Background(), "test-flag", "default", tt.evalCtx)
+		assert.Equal(t, tt.want, res.Value)
+		assert.True(t, res.FlagMetadata[MetadataKeyCached].(bool))
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+
+	// Routes share the HTTP client.
+	billing, err := r.Provider(Route{Application: "billing", Environment: "staging"})
+	assert.NoError(t, err)
+	fallback, err := r.Provider(Route{Application: "default-app", Environment: "production"})
+	assert.NoError(t, err)
+	assert.NotSame(t, billing, fallback)
+	assert.Same(t, billing.client.(*Client).httpClient, fallback.client.(*Client).httpClient)
+
+	// Routes outside the allowlist are rejected without creating a provider.
+	res := r.StringEvaluation(context.Background(), "test-flag", "default",
+		openfeature.FlattenedContext{"targetingKey": "user-1", "application": "unknown-app"})
+	assert.Equal(t, "default", res.Value)
+	assert.Equal(t, openfeature.InvalidContextCode, res.ResolutionDetail().ErrorCode)
+	_, err = r.Provider(Route{Application: "unknown-app", Environment: "production"})
+	assert.ErrorIs(t, err, ErrUnknownRoute)
+	assert.Len(t, r.providers, 5)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestRoutingProvider_Router(t *testing.T) {
+	server, _ := newRoutingServer(t)
+
+	config := routingConfig(server.URL)
+	config.Router = func(evalCtx openfeature.FlattenedContext) (Route, error) {
+		tenant, ok := evalCtx["tenant"].(string)
+		if !ok {
+			return Route{}, errors.New("missing tenant")
+		}
+		return Route{Application: tenant, Environment: "production"}, nil
+	}
+	r, err := NewRoutingProvider(config)
+	assert.NoError(t, err)
+
+	res := r.StringEvaluation(context.Background(), "test-flag", "default", openfeature.FlattenedContext{"targetingKey": "user-1", "tenant": "acme"})
+	assert.Equal(t, "acme/production", res.Value)
+
+	res = r.StringEvaluation(context.Background(), "test-flag", "default", openfeature.FlattenedContext{"targetingKey": "user-1"})
+	assert.Equal(t, "default", res.Value)
+	assert.Equal(t, openfeature.InvalidContextCode, res.ResolutionDetail().ErrorCode)
+
+	// Routes outside the allowlist fall back to the default value.
+	res = r.StringEvaluation(context.Background(), "test-flag", "default", openfeature.FlattenedContext{"targetingKey": "user-1", "tenant": ""})
+	assert.Equal(t, "default", res.Value)
+	assert.Equal(t, openfeature.InvalidContextCode, res.ResolutionDetail().ErrorCode)
+}
+
+func TestRoutingProvider_OpenFeature(t *testing.T) {
+	server, _ := newRoutingServer(t)
+
+	r, err := NewRoutingProvider(routingConfig(server.URL))
+	assert.NoError(t, err)
+	assert.NoError(t, openfeature.SetNamedProviderAndWait("routing-test", r))
+	assert.NoError(t, r.Bind("routing-test-billing", Route{Application: "billing", Environment: "staging"}))
+
+	client := openfeature.NewClient("routing-test")
+	value, err := client.StringValue(context.Background(), "test-flag", "default",
+		openfeature.NewEvaluationContext("user-1", map[string]interface{}{"application": "search"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "search/production", value)
+
+	client = openfeature.NewClient("routing-test-billing")
+	value, err = client.StringValue(context.Background(), "test-flag", "default",
+		openfeature.NewEvaluationContext("user-1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "billing/staging", value)
+}
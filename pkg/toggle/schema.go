@@ -0,0 +1,244 @@
+package toggle
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// AttributeType is the JSON type a context attribute must have.
+type AttributeType string
+
+const (
+	AttributeAny     AttributeType = ""
+	AttributeString  AttributeType = "string"
+	AttributeNumber  AttributeType = "number"
+	AttributeBoolean AttributeType = "boolean"
+	AttributeList    AttributeType = "list"
+	AttributeObject  AttributeType = "object"
+)
+
+// ContextSchema describes the Hyphen evaluation contexts an application is allowed
+// to send. It is checked after the ContextMapper runs and before the request is made.
+// Every attribute must also be JSON serializable; channels, functions and the like
+// are always rejected, as are NaN and infinite numbers. Zero limits are not enforced.
+type ContextSchema struct {
+	// Attributes declares the allowed custom attributes and their types. Top-level
+	// attributes use their name, such as "plan"; user custom attributes are prefixed
+	// with "user.", such as "user.role".
+	Attributes map[string]AttributeType
+	// AllowUnknown accepts attributes that are not declared in Attributes.
+	AllowUnknown bool
+	// MaxAttributes limits the number of top-level and of user custom attributes.
+	MaxAttributes int
+	// MaxStringLength limits the length of every string value, including the user fields.
+	MaxStringLength int
+	// MaxCollectionSize limits the number of entries in list and object values.
+	MaxCollectionSize int
+	// MaxDepth limits how deeply list and object values may be nested.
+	// An attribute holding a scalar has depth 1.
+	MaxDepth int
+	// WarnOnly reports violations through Warn and sends the context anyway.
+	WarnOnly bool
+	// Warn receives violations in WarnOnly mode, where it is required.
+	Warn func(err error)
+}
+
+// ContextViolation is a single schema violation at an attribute path.
+type ContextViolation struct {
+	Path    string
+	Message string
+}
+
+// ContextValidationError lists every violation found in an evaluation context.
+// It matches ErrInvalidContext with errors.Is.
+type ContextValidationError struct {
+	Violations []ContextViolation
+}
+
+func (e *ContextValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Path + ": " + v.Message
+	}
+	return ErrInvalidContext.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *ContextValidationError) Is(target error) bool {
+	return target == ErrInvalidContext
+}
+
+// Validate checks ctx against the schema. It returns a *ContextValidationError
+// describing every violation, or nil.
+func (s *ContextSchema) Validate(ctx EvaluationContext) error {
+	v := &contextValidator{schema: s}
+
+	v.checkString("targetingKey", ctx.TargetingKey)
+	v.checkString("ipAddress", ctx.IPAddress)
+	if ctx.User != nil {
+		v.checkString("user.id", ctx.User.ID)
+		v.checkString("user.email", ctx.User.Email)
+		v.checkString("user.name", ctx.User.Name)
+		v.checkAttributes("user.customAttributes.", "user.", ctx.User.CustomAttributes)
+	}
+	v.checkAttributes("customAttributes.", "", ctx.CustomAttributes)
+
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ContextValidationError{Violations: v.violations}
+}
+
+// check validates ctx and applies the WarnOnly policy.
+func (s *ContextSchema) check(ctx EvaluationContext) error {
+	err := s.Validate(ctx)
+	if err == nil || !s.WarnOnly {
+		return err
+	}
+	if s.Warn != nil {
+		s.Warn(err)
+	}
+	return nil
+}
+
+type contextValidator struct {
+	schema     *ContextSchema
+	violations []ContextViolation
+}
+
+func (v *contextValidator) violate(path, format string, args ...interface{}) {
+	v.violations = append(v.violations, ContextViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *contextValidator) checkString(path, value string) {
+	if max := v.schema.MaxStringLength; max > 0 && len(value) > max {
+		v.violate(path, "length %d exceeds maximum of %d", len(value), max)
+	}
+}
+
+// checkAttributes validates an attribute map. pathPrefix is used in messages and
+// schemaPrefix to look attributes up in ContextSchema.Attributes.
+func (v *contextValidator) checkAttributes(pathPrefix, schemaPrefix string, attributes map[string]interface{}) {
+	if max := v.schema.MaxAttributes; max > 0 && len(attributes) > max {
+		v.violate(strings.TrimSuffix(pathPrefix, "."), "%d attributes exceed maximum of %d", len(attributes), max)
+	}
+
+	for _, key := range sortedKeys(attributes) {
+		path := pathPrefix + key
+		value := attributes[key]
+
+		want, declared := v.schema.Attributes[schemaPrefix+key]
+		if !declared && !v.schema.AllowUnknown {
+			v.violate(path, "attribute is not allowed by the schema")
+			continue
+		}
+		if want != AttributeAny && value != nil {
+			if got := attributeType(reflect.ValueOf(value)); got != want {
+				v.violate(path, "expected %s, got %s", want, describeType(value, got))
+				continue
+			}
+		}
+		v.checkValue(path, reflect.ValueOf(value), 1)
+	}
+}
+
+func (v *contextValidator) checkValue(path string, rv reflect.Value, depth int) {
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return
+	}
+
+	if max := v.schema.MaxDepth; max > 0 && depth > max {
+		v.violate(path, "nesting depth %d exceeds maximum of %d", depth, max)
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		v.checkString(path, rv.String())
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Float32, reflect.Float64:
+		// encoding/json cannot represent NaN or infinities.
+		if f := rv.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			v.violate(path, "value %v is not JSON serializable", f)
+		}
+	case reflect.Slice, reflect.Array:
+		v.checkSize(path, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			v.checkValue(fmt.Sprintf("%s[%d]", path, i), rv.Index(i), depth+1)
+		}
+	case reflect.Map:
+		v.checkSize(path, rv.Len())
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, k := range keys {
+			v.checkValue(fmt.Sprintf("%s.%v", path, k), rv.MapIndex(k), depth+1)
+		}
+	default:
+		// Structs and other values are accepted as long as they encode as JSON.
+		if _, err := json.Marshal(rv.Interface()); err != nil {
+			v.violate(path, "value of type %s is not JSON serializable", rv.Type())
+		}
+	}
+}
+
+func (v *contextValidator) checkSize(path string, size int) {
+	if max := v.schema.MaxCollectionSize; max > 0 && size > max {
+		v.violate(path, "%d entries exceed maximum of %d", size, max)
+	}
+}
+
+// attributeType returns the AttributeType of rv, or "" for values that have none.
+func attributeType(rv reflect.Value) AttributeType {
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return AttributeAny
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		if _, ok := rv.Interface().(json.Number); ok {
+			return AttributeNumber
+		}
+		return AttributeString
+	case reflect.Bool:
+		return AttributeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return AttributeNumber
+	case reflect.Slice, reflect.Array:
+		return AttributeList
+	case reflect.Map, reflect.Struct:
+		return AttributeObject
+	}
+	return AttributeAny
+}
+
+func describeType(value interface{}, t AttributeType) string {
+	if t == AttributeAny {
+		return fmt.Sprintf("%T", value)
+	}
+	return string(t)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
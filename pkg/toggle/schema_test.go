@@ -0,0 +1,198 @@
+package toggle
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextSchema_Validate(t *testing.T) {
+	schema := &ContextSchema{
+		Attributes: map[string]AttributeType{
+			"plan":      AttributeString,
+			"seats":     AttributeNumber,
+			"beta":      AttributeBoolean,
+			"tags":      AttributeList,
+			"settings":  AttributeObject,
+			"user.role": AttributeString,
+		},
+		MaxAttributes:     5,
+		MaxStringLength:   10,
+		MaxCollectionSize: 3,
+		MaxDepth:          2,
+	}
+
+	tests := []struct {
+		name  string
+		ctx   EvaluationContext
+		paths []string
+	}{
+		{
+			name: "valid",
+			ctx: EvaluationContext{
+				TargetingKey: "user-123",
+				User: &User{
+					ID:               "user-123",
+					CustomAttributes: map[string]interface{}{"role": "admin"},
+				},
+				CustomAttributes: map[string]interface{}{
+					"plan":     "pro",
+					"seats":    25,
+					"beta":     true,
+					"tags":     []interface{}{"a", "b"},
+					"settings": map[string]interface{}{"theme": "dark"},
+				},
+			},
+		},
+		{
+			name: "unknown attributes",
+			ctx: EvaluationContext{
+				User:             &User{CustomAttributes: map[string]interface{}{"team": "core"}},
+				CustomAttributes: map[string]interface{}{"region": "us"},
+			},
+			paths: []string{"user.customAttributes.team", "customAttributes.region"},
+		},
+		{
+			name: "wrong types",
+			ctx: EvaluationContext{
+				CustomAttributes: map[string]interface{}{
+					"plan":  42,
+					"seats": "25",
+					"beta":  func() {},
+				},
+			},
+			paths: []string{"customAttributes.beta", "customAttributes.plan", "customAttributes.seats"},
+		},
+		{
+			name: "limits",
+			ctx: EvaluationContext{
+				TargetingKey: "a-very-long-targeting-key",
+				CustomAttributes: map[string]interface{}{
+					"tags":     []interface{}{"a", "b", "c", "d"},
+					"settings": map[string]interface{}{"nested": map[string]interface{}{"deep": true}},
+				},
+			},
+			paths: []string{"targetingKey", "customAttributes.settings.nested.deep", "customAttributes.tags"},
+		},
+		{
+			name: "too many attributes",
+			ctx: EvaluationContext{
+				CustomAttributes: map[string]interface{}{
+					"plan": "a", "seats": 1, "beta": true, "tags": nil, "settings": nil, "user": nil,
+				},
+			},
+			paths: []string{"customAttributes", "customAttributes.user"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate(tt.ctx)
+			if len(tt.paths) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrInvalidContext)
+			validationErr, ok := err.(*ContextValidationError)
+			assert.True(t, ok)
+			var paths []string
+			for _, v := range validationErr.Violations {
+				paths = append(paths, v.Path)
+			}
+			assert.Equal(t, tt.paths, paths)
+		})
+	}
+}
+
+func TestContextSchema_UnserializableValues(t *testing.T) {
+	schema := &ContextSchema{AllowUnknown: true}
+
+	err := schema.Validate(EvaluationContext{
+		CustomAttributes: map[string]interface{}{
+			"ok":      map[string]interface{}{"list": []int{1, 2}},
+			"channel": make(chan int),
+			"nested":  map[string]interface{}{"fn": func() {}},
+			"nan":     math.NaN(),
+			"scores":  []float32{1, float32(math.Inf(-1))},
+		},
+	})
+	assert.ErrorIs(t, err, ErrInvalidContext)
+	assert.Contains(t, err.Error(), "customAttributes.channel: value of type chan int is not JSON serializable")
+	assert.Contains(t, err.Error(), "customAttributes.nested.fn: value of type func() is not JSON serializable")
+	assert.Contains(t, err.Error(), "customAttributes.nan: value NaN is not JSON serializable")
+	assert.Contains(t, err.Error(), "customAttributes.scores[1]: value -Inf is not JSON serializable")
+	assert.NotContains(t, err.Error(), "customAttributes.ok")
+}
+
+func TestContextSchema_WarnOnlyRequiresWarn(t *testing.T) {
+	config := Config{
+		PublicKey:     testPublicKey,
+		Application:   "test-app",
+		Environment:   "test-env",
+		ContextSchema: &ContextSchema{AllowUnknown: true, WarnOnly: true},
+	}
+
+	_, err := NewProvider(config)
+	assert.ErrorIs(t, err, ErrMissingSchemaWarn)
+	assert.ErrorIs(t, ValidateConfig(config), ErrMissingSchemaWarn)
+
+	config.ContextSchema.Warn = func(err error) {}
+	_, err = NewProvider(config)
+	assert.NoError(t, err)
+	assert.NoError(t, ValidateConfig(config))
+}
+
+func TestProvider_ContextSchema(t *testing.T) {
+	newProvider := func(schema *ContextSchema) (*Provider, *bool) {
+		called := false
+		p := &Provider{
+			config: Config{
+				Application:   "test-app",
+				Environment:   "test-env",
+				ContextSchema: schema,
+			},
+			client: &MockClient{
+				EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+					called = true
+					return &Response{Toggles: map[string]Evaluation{
+						"test-flag": {Type: "boolean", Value: true},
+					}}, nil
+				},
+			},
+		}
+		return p, &called
+	}
+	evalCtx := openfeature.FlattenedContext{
+		"targetingKey": "user-123",
+		"plan":         strings.Repeat("x", 20),
+	}
+
+	t.Run("enforced", func(t *testing.T) {
+		p, called := newProvider(&ContextSchema{AllowUnknown: true, MaxStringLength: 10})
+		result := p.BooleanEvaluation(context.Background(), "test-flag", false, evalCtx)
+		assert.False(t, result.Value)
+		assert.False(t, *called)
+		assert.Equal(t, openfeature.InvalidContextCode, result.ResolutionDetail().ErrorCode)
+		assert.Contains(t, result.ResolutionDetail().ErrorMessage, "customAttributes.plan: length 20 exceeds maximum of 10")
+	})
+
+	t.Run("warn only", func(t *testing.T) {
+		var warnings []error
+		p, called := newProvider(&ContextSchema{
+			AllowUnknown:    true,
+			MaxStringLength: 10,
+			WarnOnly:        true,
+			Warn:            func(err error) { warnings = append(warnings, err) },
+		})
+		result := p.BooleanEvaluation(context.Background(), "test-flag", false, evalCtx)
+		assert.True(t, result.Value)
+		assert.True(t, *called)
+		assert.Len(t, warnings, 1)
+		assert.ErrorIs(t, warnings[0], ErrInvalidContext)
+	})
+}
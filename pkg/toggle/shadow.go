@@ -0,0 +1,398 @@
+package toggle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"golang.org/x/exp/rand"
+)
+
+// DefaultShadowQueueSize is how many comparisons may wait for the shadow
+// provider when ShadowConfig.QueueSize is not set.
+const DefaultShadowQueueSize = 1000
+
+// MismatchKind is a way a shadow evaluation can differ from the primary one.
+type MismatchKind string
+
+const (
+	// MismatchValue means both providers resolved different values.
+	MismatchValue MismatchKind = "value"
+	// MismatchType means the providers disagree on the flag's type: one of them
+	// reported a type mismatch or resolved a value of another Go type.
+	MismatchType MismatchKind = "type"
+	// MismatchReason means the providers resolved with different reasons or error codes.
+	MismatchReason MismatchKind = "reason"
+)
+
+// Mismatch records an evaluation where the shadow provider disagreed with the primary one.
+type Mismatch struct {
+	Flag             string                       `json:"flag"`
+	FlagType         string                       `json:"flagType"`
+	Kinds            []MismatchKind               `json:"kinds"`
+	PrimaryValue     interface{}                  `json:"primaryValue"`
+	ShadowValue      interface{}                  `json:"shadowValue"`
+	PrimaryReason    openfeature.Reason           `json:"primaryReason,omitempty"`
+	ShadowReason     openfeature.Reason           `json:"shadowReason,omitempty"`
+	PrimaryErrorCode openfeature.ErrorCode        `json:"primaryErrorCode,omitempty"`
+	ShadowErrorCode  openfeature.ErrorCode        `json:"shadowErrorCode,omitempty"`
+	Context          openfeature.FlattenedContext `json:"context"`
+	Timestamp        time.Time                    `json:"timestamp"`
+}
+
+// Has reports whether the mismatch includes kind.
+func (m Mismatch) Has(kind MismatchKind) bool {
+	for _, k := range m.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// MismatchSink receives the mismatches found by a ShadowProvider. WriterSink,
+// MemorySink and WebhookSink implement it.
+type MismatchSink interface {
+	SendMismatch(mismatch Mismatch) error
+}
+
+// MismatchCounter counts mismatches, for example with a metrics counter labelled
+// by flag and kind. Inc is called once per kind of each mismatch.
+type MismatchCounter interface {
+	Inc(flag string, kind MismatchKind)
+}
+
+// ShadowConfig configures a ShadowProvider.
+type ShadowConfig struct {
+	// Primary answers every evaluation, typically the legacy flag system.
+	Primary openfeature.FeatureProvider
+	// Shadow is compared against Primary, typically the Hyphen Provider.
+	Shadow openfeature.FeatureProvider
+	// SampleRate is the fraction of evaluations compared, in (0, 1]. When zero,
+	// every evaluation is compared.
+	SampleRate float64
+	// QueueSize bounds the comparisons waiting for the shadow provider. When the
+	// queue is full new comparisons are dropped. Defaults to DefaultShadowQueueSize.
+	QueueSize int
+	Sinks     []MismatchSink
+	Counter   MismatchCounter
+}
+
+// ShadowStats counts the work done by a ShadowProvider.
+type ShadowStats struct {
+	Compared   uint64
+	Mismatched uint64
+	Dropped    uint64
+}
+
+// ShadowProvider returns the evaluations of a primary provider while comparing
+// them with a shadow provider in the background, which lets a migration to Hyphen
+// be verified on live traffic. Shadow evaluations run on a separate goroutine, so
+// they add no latency. The Before hooks of the shadow provider run on a copy of
+// the context first, so it sees the context the client would give it; its other
+// hooks do not run, so shadow evaluations are not reported as usage.
+type ShadowProvider struct {
+	primary    openfeature.FeatureProvider
+	shadow     openfeature.FeatureProvider
+	sampleRate float64
+	sinks      []MismatchSink
+	counter    MismatchCounter
+
+	queue   chan shadowJob
+	pending sync.WaitGroup
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+
+	compared   uint64
+	mismatched uint64
+	dropped    uint64
+}
+
+// shadowJob is a primary evaluation waiting to be compared.
+type shadowJob struct {
+	ctx          context.Context
+	flag         string
+	flagType     openfeature.Type
+	defaultValue interface{}
+	evalCtx      openfeature.FlattenedContext
+	primary      openfeature.InterfaceResolutionDetail
+}
+
+func NewShadowProvider(config ShadowConfig) (*ShadowProvider, error) {
+	if config.Primary == nil || config.Shadow == nil {
+		return nil, ErrMissingShadowProvider
+	}
+	sampleRate := config.SampleRate
+	if sampleRate == 0 {
+		sampleRate = 1
+	}
+	if sampleRate < 0 || sampleRate > 1 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSampleRate, config.SampleRate)
+	}
+	queueSize := config.QueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultShadowQueueSize
+	}
+
+	s := &ShadowProvider{
+		primary:    config.Primary,
+		shadow:     config.Shadow,
+		sampleRate: sampleRate,
+		sinks:      config.Sinks,
+		counter:    config.Counter,
+		queue:      make(chan shadowJob, queueSize),
+		done:       make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+func (s *ShadowProvider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{
+		Name: "hyphen-shadow-provider",
+	}
+}
+
+// Hooks returns the hooks of the primary provider, which answers every evaluation.
+func (s *ShadowProvider) Hooks() []openfeature.Hook {
+	return s.primary.Hooks()
+}
+
+// Init initializes both providers. Only a failure of the primary provider is returned.
+func (s *ShadowProvider) Init(evalCtx openfeature.EvaluationContext) error {
+	if handler, ok := s.shadow.(openfeature.StateHandler); ok {
+		_ = handler.Init(evalCtx)
+	}
+	if handler, ok := s.primary.(openfeature.StateHandler); ok {
+		return handler.Init(evalCtx)
+	}
+	return nil
+}
+
+// Shutdown stops accepting comparisons, waits for the queued ones and shuts
+// both providers down.
+func (s *ShadowProvider) Shutdown() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	<-s.done
+
+	for _, p := range []openfeature.FeatureProvider{s.primary, s.shadow} {
+		if handler, ok := p.(openfeature.StateHandler); ok {
+			handler.Shutdown()
+		}
+	}
+}
+
+// Flush waits until the comparisons queued so far have been reported.
+func (s *ShadowProvider) Flush() {
+	s.pending.Wait()
+}
+
+func (s *ShadowProvider) Stats() ShadowStats {
+	return ShadowStats{
+		Compared:   atomic.LoadUint64(&s.compared),
+		Mismatched: atomic.LoadUint64(&s.mismatched),
+		Dropped:    atomic.LoadUint64(&s.dropped),
+	}
+}
+
+func (s *ShadowProvider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	res := s.primary.BooleanEvaluation(ctx, flag, defaultValue, evalCtx)
+	s.enqueue(ctx, flag, openfeature.Boolean, defaultValue, evalCtx, res.Value, res.ProviderResolutionDetail)
+	return res
+}
+
+func (s *ShadowProvider) StringEvaluation(ctx context.Context, flag string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	res := s.primary.StringEvaluation(ctx, flag, defaultValue, evalCtx)
+	s.enqueue(ctx, flag, openfeature.String, defaultValue, evalCtx, res.Value, res.ProviderResolutionDetail)
+	return res
+}
+
+func (s *ShadowProvider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	res := s.primary.FloatEvaluation(ctx, flag, defaultValue, evalCtx)
+	s.enqueue(ctx, flag, openfeature.Float, defaultValue, evalCtx, res.Value, res.ProviderResolutionDetail)
+	return res
+}
+
+func (s *ShadowProvider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	res := s.primary.IntEvaluation(ctx, flag, defaultValue, evalCtx)
+	s.enqueue(ctx, flag, openfeature.Int, defaultValue, evalCtx, res.Value, res.ProviderResolutionDetail)
+	return res
+}
+
+func (s *ShadowProvider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	res := s.primary.ObjectEvaluation(ctx, flag, defaultValue, evalCtx)
+	s.enqueue(ctx, flag, openfeature.Object, defaultValue, evalCtx, res.Value, res.ProviderResolutionDetail)
+	return res
+}
+
+// Track forwards the event to the primary provider if it supports tracking.
+func (s *ShadowProvider) Track(ctx context.Context, trackingEventName string, evalCtx openfeature.EvaluationContext, details openfeature.TrackingEventDetails) {
+	if tracker, ok := s.primary.(openfeature.Tracker); ok {
+		tracker.Track(ctx, trackingEventName, evalCtx, details)
+	}
+}
+
+// enqueue samples the primary evaluation and queues it for comparison without
+// blocking. The evaluation context is copied, since callers may reuse it.
+func (s *ShadowProvider) enqueue(ctx context.Context, flag string, flagType openfeature.Type, defaultValue interface{}, evalCtx openfeature.FlattenedContext, value interface{}, detail openfeature.ProviderResolutionDetail) {
+	if s.sampleRate < 1 && rand.Float64() >= s.sampleRate {
+		return
+	}
+
+	job := shadowJob{
+		ctx:          context.WithoutCancel(ctx),
+		flag:         flag,
+		flagType:     flagType,
+		defaultValue: defaultValue,
+		evalCtx:      make(openfeature.FlattenedContext, len(evalCtx)),
+		primary:      openfeature.InterfaceResolutionDetail{Value: value, ProviderResolutionDetail: detail},
+	}
+	for k, v := range evalCtx {
+		job.evalCtx[k] = v
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+	s.pending.Add(1)
+	select {
+	case s.queue <- job:
+	default:
+		s.pending.Done()
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+func (s *ShadowProvider) run() {
+	defer close(s.done)
+	for job := range s.queue {
+		s.compare(job)
+		s.pending.Done()
+	}
+}
+
+// compare evaluates job with the shadow provider and reports any mismatch.
+func (s *ShadowProvider) compare(job shadowJob) {
+	shadow := s.evaluateShadow(job)
+	atomic.AddUint64(&s.compared, 1)
+
+	mismatch, ok := compareResolutions(job, shadow)
+	if !ok {
+		return
+	}
+	atomic.AddUint64(&s.mismatched, 1)
+
+	if s.counter != nil {
+		for _, kind := range mismatch.Kinds {
+			s.counter.Inc(mismatch.Flag, kind)
+		}
+	}
+	for _, sink := range s.sinks {
+		if err := sink.SendMismatch(mismatch); err != nil {
+			logError(job.ctx, "Error reporting shadow mismatch:", err)
+		}
+	}
+}
+
+// evaluateShadow runs the shadow provider's Before hooks on the job's context and
+// evaluates the flag with the result. A failing hook resolves to the default value
+// with a GENERAL error, as the OpenFeature client would.
+func (s *ShadowProvider) evaluateShadow(job shadowJob) openfeature.InterfaceResolutionDetail {
+	hookContext := openfeature.NewHookContext(job.flag, job.flagType, job.defaultValue,
+		openfeature.ClientMetadata{}, s.shadow.Metadata(), unflattenEvaluationContext(job.evalCtx))
+	hookedCtx, err := runBeforeHooks(job.ctx, s.shadow, hookContext, openfeature.HookHints{})
+	if err != nil {
+		return openfeature.InterfaceResolutionDetail{
+			Value:                    job.defaultValue,
+			ProviderResolutionDetail: errorDetail(openfeature.NewGeneralResolutionError(err.Error())),
+		}
+	}
+	evalCtx := flattenEvaluationContext(hookedCtx)
+
+	switch job.flagType {
+	case openfeature.Boolean:
+		res := s.shadow.BooleanEvaluation(job.ctx, job.flag, job.defaultValue.(bool), evalCtx)
+		return openfeature.InterfaceResolutionDetail{Value: res.Value, ProviderResolutionDetail: res.ProviderResolutionDetail}
+	case openfeature.String:
+		res := s.shadow.StringEvaluation(job.ctx, job.flag, job.defaultValue.(string), evalCtx)
+		return openfeature.InterfaceResolutionDetail{Value: res.Value, ProviderResolutionDetail: res.ProviderResolutionDetail}
+	case openfeature.Float:
+		res := s.shadow.FloatEvaluation(job.ctx, job.flag, job.defaultValue.(float64), evalCtx)
+		return openfeature.InterfaceResolutionDetail{Value: res.Value, ProviderResolutionDetail: res.ProviderResolutionDetail}
+	case openfeature.Int:
+		res := s.shadow.IntEvaluation(job.ctx, job.flag, job.defaultValue.(int64), evalCtx)
+		return openfeature.InterfaceResolutionDetail{Value: res.Value, ProviderResolutionDetail: res.ProviderResolutionDetail}
+	default:
+		return s.shadow.ObjectEvaluation(job.ctx, job.flag, job.defaultValue, evalCtx)
+	}
+}
+
+// compareResolutions returns the mismatch between the primary and shadow
+// resolutions of job, or false when they agree.
+func compareResolutions(job shadowJob, shadow openfeature.InterfaceResolutionDetail) (Mismatch, bool) {
+	primary := job.primary
+	primaryCode := primary.ResolutionDetail().ErrorCode
+	shadowCode := shadow.ResolutionDetail().ErrorCode
+
+	primaryValue, shadowValue := normalizeValue(primary.Value), normalizeValue(shadow.Value)
+	var kinds []MismatchKind
+	switch {
+	case (primaryCode == openfeature.TypeMismatchCode) != (shadowCode == openfeature.TypeMismatchCode),
+		reflect.TypeOf(primaryValue) != reflect.TypeOf(shadowValue):
+		kinds = append(kinds, MismatchType)
+	case !reflect.DeepEqual(primaryValue, shadowValue):
+		kinds = append(kinds, MismatchValue)
+	}
+	if primary.Reason != shadow.Reason || primaryCode != shadowCode {
+		kinds = append(kinds, MismatchReason)
+	}
+	if len(kinds) == 0 {
+		return Mismatch{}, false
+	}
+
+	return Mismatch{
+		Flag:             job.flag,
+		FlagType:         typeToString[job.flagType],
+		Kinds:            kinds,
+		PrimaryValue:     primary.Value,
+		ShadowValue:      shadow.Value,
+		PrimaryReason:    primary.Reason,
+		ShadowReason:     shadow.Reason,
+		PrimaryErrorCode: primaryCode,
+		ShadowErrorCode:  shadowCode,
+		Context:          job.evalCtx,
+		Timestamp:        time.Now().UTC(),
+	}, true
+}
+
+// normalizeValue converts object values to their JSON form, so objects that
+// encode the same, such as a struct and a map, compare equal.
+func normalizeValue(v interface{}) interface{} {
+	switch v.(type) {
+	case nil, bool, string, float64, int64:
+		return v
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return v
+	}
+	return normalized
+}
@@ -0,0 +1,175 @@
+package toggle
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingMismatches struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (c *countingMismatches) Inc(flag string, kind MismatchKind) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[flag+":"+string(kind)]++
+}
+
+func TestNewShadowProvider(t *testing.T) {
+	local, err := NewLocalProvider(nil)
+	assert.NoError(t, err)
+
+	_, err = NewShadowProvider(ShadowConfig{Primary: local})
+	assert.ErrorIs(t, err, ErrMissingShadowProvider)
+
+	_, err = NewShadowProvider(ShadowConfig{Primary: local, Shadow: local, SampleRate: 1.5})
+	assert.ErrorIs(t, err, ErrInvalidSampleRate)
+}
+
+func TestShadowProvider(t *testing.T) {
+	legacy, err := NewLocalProvider(map[string]interface{}{
+		"same-flag":   "blue",
+		"value-flag":  "blue",
+		"type-flag":   "blue",
+		"object-flag": map[string]interface{}{"limit": 10},
+	})
+	assert.NoError(t, err)
+	hyphen := newChainPrimary(func(ctx EvaluationContext) (*Response, error) {
+		return &Response{Toggles: map[string]Evaluation{
+			"same-flag":   {Key: "same-flag", Type: "string", Value: "blue", Reason: "static"},
+			"value-flag":  {Key: "value-flag", Type: "string", Value: "green", Reason: "static"},
+			"type-flag":   {Key: "type-flag", Type: "boolean", Value: true},
+			"object-flag": {Key: "object-flag", Type: "object", Value: map[string]interface{}{"limit": 10.0}, Reason: "static"},
+		}}, nil
+	})
+
+	sink := NewMemorySink()
+	counter := &countingMismatches{counts: map[string]int{}}
+	shadow, err := NewShadowProvider(ShadowConfig{
+		Primary: legacy,
+		Shadow:  hyphen,
+		Sinks:   []MismatchSink{sink},
+		Counter: counter,
+	})
+	assert.NoError(t, err)
+	defer shadow.Shutdown()
+
+	ctx := context.Background()
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	for _, flag := range []string{"same-flag", "value-flag", "type-flag"} {
+		// The legacy value is always returned.
+		assert.Equal(t, "blue", shadow.StringEvaluation(ctx, flag, "default", evalCtx).Value)
+	}
+	assert.Equal(t, map[string]interface{}{"limit": 10.0}, shadow.ObjectEvaluation(ctx, "object-flag", nil, evalCtx).Value)
+	missing := shadow.StringEvaluation(ctx, "missing-flag", "default", evalCtx)
+	assert.Equal(t, "default", missing.Value)
+	shadow.Flush()
+
+	mismatches := map[string]Mismatch{}
+	for _, m := range sink.Mismatches() {
+		mismatches[m.Flag] = m
+	}
+	assert.Len(t, mismatches, 2)
+
+	valueMismatch := mismatches["value-flag"]
+	assert.Equal(t, []MismatchKind{MismatchValue}, valueMismatch.Kinds)
+	assert.Equal(t, "blue", valueMismatch.PrimaryValue)
+	assert.Equal(t, "green", valueMismatch.ShadowValue)
+	assert.Equal(t, "string", valueMismatch.FlagType)
+	assert.Equal(t, evalCtx, valueMismatch.Context)
+
+	typeMismatch := mismatches["type-flag"]
+	assert.True(t, typeMismatch.Has(MismatchType))
+	assert.True(t, typeMismatch.Has(MismatchReason))
+	assert.Equal(t, openfeature.TypeMismatchCode, typeMismatch.ShadowErrorCode)
+
+	assert.Equal(t, map[string]int{"value-flag:value": 1, "type-flag:type": 1, "type-flag:reason": 1}, counter.counts)
+	assert.Equal(t, ShadowStats{Compared: 5, Mismatched: 2}, shadow.Stats())
+}
+
+func TestShadowProvider_Sampling(t *testing.T) {
+	legacy, err := NewLocalProvider(map[string]interface{}{"flag": true})
+	assert.NoError(t, err)
+	blocked := make(chan struct{})
+	hyphen := newChainPrimary(func(ctx EvaluationContext) (*Response, error) {
+		<-blocked
+		return &Response{Toggles: map[string]Evaluation{"flag": {Key: "flag", Type: "boolean", Value: true, Reason: "static"}}}, nil
+	})
+
+	shadow, err := NewShadowProvider(ShadowConfig{Primary: legacy, Shadow: hyphen, SampleRate: 0.5, QueueSize: 1})
+	assert.NoError(t, err)
+
+	// With the shadow provider blocked, at most two comparisons are in flight and
+	// the remaining sampled ones are dropped instead of delaying evaluations.
+	for i := 0; i < 1000; i++ {
+		assert.True(t, shadow.BooleanEvaluation(context.Background(), "flag", false, openfeature.FlattenedContext{"targetingKey": "user-1"}).Value)
+	}
+	close(blocked)
+	shadow.Shutdown()
+
+	stats := shadow.Stats()
+	assert.LessOrEqual(t, stats.Compared, uint64(2))
+	assert.Greater(t, stats.Compared+stats.Dropped, uint64(300))
+	assert.Less(t, stats.Compared+stats.Dropped, uint64(700))
+	assert.Zero(t, stats.Mismatched)
+}
+
+func TestShadowProvider_RunsShadowBeforeHooks(t *testing.T) {
+	legacy, err := NewLocalProvider(map[string]interface{}{"flag": "blue"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		anonymousKey AnonymousKeyStrategy
+		wantCode     openfeature.ErrorCode
+	}{
+		{name: "context prepared by hooks"},
+		{name: "failing hook", anonymousKey: RejectAnonymous(), wantCode: openfeature.GeneralCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contexts []EvaluationContext
+			hyphen, err := NewProviderWithClient(Config{
+				PublicKey:    testPublicKey,
+				Application:  "test-app",
+				Environment:  "test-env",
+				AnonymousKey: tt.anonymousKey,
+			}, &MockClient{EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+				contexts = append(contexts, ctx)
+				return &Response{Toggles: map[string]Evaluation{
+					"flag": {Key: "flag", Type: "string", Value: "blue", Reason: "static"},
+				}}, nil
+			}})
+			assert.NoError(t, err)
+
+			sink := NewMemorySink()
+			shadow, err := NewShadowProvider(ShadowConfig{Primary: legacy, Shadow: hyphen, Sinks: []MismatchSink{sink}})
+			assert.NoError(t, err)
+			defer shadow.Shutdown()
+
+			evalCtx := openfeature.FlattenedContext{"plan": "pro"}
+			assert.Equal(t, "blue", shadow.StringEvaluation(context.Background(), "flag", "default", evalCtx).Value)
+			shadow.Flush()
+
+			// The primary's context is left untouched.
+			assert.Equal(t, openfeature.FlattenedContext{"plan": "pro"}, evalCtx)
+			if tt.wantCode != "" {
+				assert.Empty(t, contexts)
+				assert.Len(t, sink.Mismatches(), 1)
+				assert.Equal(t, tt.wantCode, sink.Mismatches()[0].ShadowErrorCode)
+				return
+			}
+			assert.Empty(t, sink.Mismatches())
+			assert.Len(t, contexts, 1)
+			assert.NotEmpty(t, contexts[0].TargetingKey)
+			assert.Equal(t, "test-app", contexts[0].CustomAttributes["application"])
+			assert.Equal(t, "pro", contexts[0].CustomAttributes["plan"])
+		})
+	}
+}
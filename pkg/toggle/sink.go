@@ -0,0 +1,372 @@
+package toggle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// TelemetrySink receives a copy of every usage telemetry payload produced by the provider.
+// Sinks that also implement ExposureSink or TrackingSink receive exposures and tracking events.
+// The Horizon Client is itself a TelemetrySink and remains the default destination.
+type TelemetrySink interface {
+	SendTelemetry(payload TelemetryPayload) error
+}
+
+// TrackingSink receives custom tracking events recorded through Provider.Track.
+type TrackingSink interface {
+	SendTrackingEvent(payload TrackingPayload) error
+}
+
+// Kinds of telemetry written by the built-in sinks.
+const (
+	TelemetryKindUsage    = "usage"
+	TelemetryKindExposure = "exposure"
+	TelemetryKindTracking = "tracking"
+	TelemetryKindMismatch = "mismatch"
+)
+
+// TelemetryEnvelope wraps a telemetry payload with its kind, as written by WriterSink and WebhookSink.
+type TelemetryEnvelope struct {
+	Kind      string      `json:"kind"`
+	Timestamp time.Time   `json:"timestamp"`
+	Payload   interface{} `json:"payload"`
+}
+
+func newEnvelope(kind string, payload interface{}) TelemetryEnvelope {
+	return TelemetryEnvelope{
+		Kind:      kind,
+		Timestamp: time.Now().UTC(),
+		Payload:   payload,
+	}
+}
+
+// usageEnabled reports whether usage telemetry should be sent to Horizon.
+func (p *Provider) usageEnabled() bool {
+	enableUsage := p.settings().EnableUsage
+	return enableUsage == nil || *enableUsage
+}
+
+// sendTelemetry delivers payload to Horizon, when usage is enabled, and to every
+// configured sink. Delivery continues past failing sinks.
+func (p *Provider) sendTelemetry(payload TelemetryPayload) error {
+	var errs []error
+	if p.usageEnabled() {
+		if err := p.client.SendTelemetry(payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, sink := range p.telemetrySinks() {
+		if err := sink.SendTelemetry(payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendTrackingEvent delivers payload to Horizon, when the client implements
+// TrackingClient, and to every sink that accepts tracking events.
+func (p *Provider) sendTrackingEvent(payload TrackingPayload) error {
+	var errs []error
+	if client, ok := p.client.(TrackingClient); ok {
+		if err := client.SendTrackingEvent(payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, sink := range p.telemetrySinks() {
+		if tracker, ok := sink.(TrackingSink); ok {
+			if err := tracker.SendTrackingEvent(payload); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// configuredSinks are the sinks a provider creates from Config.TelemetryFile and
+// Config.TelemetryWebhook.
+type configuredSinks struct {
+	filePath string
+	file     *WriterSink
+	webhook  *WebhookSink
+}
+
+// reconfigure returns the sinks for config. Sinks whose settings are unchanged are
+// reused, so the telemetry file is only reopened when its path changes.
+func (s configuredSinks) reconfigure(config Config) (configuredSinks, error) {
+	next := configuredSinks{filePath: config.TelemetryFile}
+	if config.TelemetryFile != "" {
+		if s.file != nil && s.filePath == config.TelemetryFile {
+			next.file = s.file
+		} else {
+			file, err := NewFileSink(config.TelemetryFile)
+			if err != nil {
+				return configuredSinks{}, err
+			}
+			next.file = file
+		}
+	}
+	if config.TelemetryWebhook != "" {
+		if s.webhook != nil && s.webhook.URL == config.TelemetryWebhook && reflect.DeepEqual(s.webhook.Headers, config.TelemetryWebhookHeaders) {
+			next.webhook = s.webhook
+		} else {
+			next.webhook = NewWebhookSink(config.TelemetryWebhook, config.TelemetryWebhookHeaders)
+		}
+	}
+	return next, nil
+}
+
+// list returns the sinks that are configured.
+func (s configuredSinks) list() []TelemetrySink {
+	var sinks []TelemetrySink
+	if s.file != nil {
+		sinks = append(sinks, s.file)
+	}
+	if s.webhook != nil {
+		sinks = append(sinks, s.webhook)
+	}
+	return sinks
+}
+
+// closeReplaced closes the telemetry file unless next still uses it.
+func (s configuredSinks) closeReplaced(next configuredSinks) error {
+	if s.file != nil && s.file != next.file {
+		return s.file.Close()
+	}
+	return nil
+}
+
+// telemetrySinks returns the sinks set in Config.TelemetrySinks followed by the
+// ones the provider created from the config.
+func telemetrySinks(config Config, configured configuredSinks) []TelemetrySink {
+	return append(append([]TelemetrySink(nil), config.TelemetrySinks...), configured.list()...)
+}
+
+// telemetrySinks returns every sink telemetry is delivered to besides Horizon.
+func (p *Provider) telemetrySinks() []TelemetrySink {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return telemetrySinks(p.config, p.sinks)
+}
+
+// exposureSinks returns the telemetry sinks that also accept exposures.
+func exposureSinks(sinks []TelemetrySink) []ExposureSink {
+	var out []ExposureSink
+	for _, sink := range sinks {
+		if exposureSink, ok := sink.(ExposureSink); ok {
+			out = append(out, exposureSink)
+		}
+	}
+	return out
+}
+
+// WriterSink writes every telemetry event as a line of newline-delimited JSON.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// NewFileSink appends newline-delimited JSON telemetry to the file at path, creating it if needed.
+// Close the sink's file with Close when the provider is no longer used.
+func NewFileSink(path string) (*WriterSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open telemetry file: %w", err)
+	}
+	return &WriterSink{w: f}, nil
+}
+
+func (s *WriterSink) SendTelemetry(payload TelemetryPayload) error {
+	return s.write(newEnvelope(TelemetryKindUsage, payload))
+}
+
+func (s *WriterSink) SendExposure(event ExposureEvent) error {
+	return s.write(newEnvelope(TelemetryKindExposure, event))
+}
+
+func (s *WriterSink) SendTrackingEvent(payload TrackingPayload) error {
+	return s.write(newEnvelope(TelemetryKindTracking, payload))
+}
+
+func (s *WriterSink) SendMismatch(mismatch Mismatch) error {
+	return s.write(newEnvelope(TelemetryKindMismatch, mismatch))
+}
+
+// Close closes the underlying writer if it is an io.Closer.
+func (s *WriterSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if closer, ok := s.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (s *WriterSink) write(envelope TelemetryEnvelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(data)
+	return err
+}
+
+// MemorySink keeps every telemetry event in memory. It is intended for tests.
+type MemorySink struct {
+	mu         sync.Mutex
+	telemetry  []TelemetryPayload
+	exposures  []ExposureEvent
+	tracking   []TrackingPayload
+	mismatches []Mismatch
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) SendTelemetry(payload TelemetryPayload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.telemetry = append(s.telemetry, payload)
+	return nil
+}
+
+func (s *MemorySink) SendExposure(event ExposureEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exposures = append(s.exposures, event)
+	return nil
+}
+
+func (s *MemorySink) SendTrackingEvent(payload TrackingPayload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tracking = append(s.tracking, payload)
+	return nil
+}
+
+func (s *MemorySink) SendMismatch(mismatch Mismatch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mismatches = append(s.mismatches, mismatch)
+	return nil
+}
+
+// Telemetry returns a copy of the usage payloads received so far.
+func (s *MemorySink) Telemetry() []TelemetryPayload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]TelemetryPayload(nil), s.telemetry...)
+}
+
+// Exposures returns a copy of the exposures received so far.
+func (s *MemorySink) Exposures() []ExposureEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ExposureEvent(nil), s.exposures...)
+}
+
+// TrackingEvents returns a copy of the tracking events received so far.
+func (s *MemorySink) TrackingEvents() []TrackingPayload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]TrackingPayload(nil), s.tracking...)
+}
+
+// Mismatches returns a copy of the shadow evaluation mismatches received so far.
+func (s *MemorySink) Mismatches() []Mismatch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Mismatch(nil), s.mismatches...)
+}
+
+// Reset discards every event received so far.
+func (s *MemorySink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.telemetry = nil
+	s.exposures = nil
+	s.tracking = nil
+	s.mismatches = nil
+}
+
+// WebhookSink posts every telemetry event as a JSON TelemetryEnvelope to URL.
+type WebhookSink struct {
+	URL        string
+	Headers    map[string]string
+	HTTPClient *http.Client
+}
+
+func NewWebhookSink(url string, headers map[string]string) *WebhookSink {
+	return &WebhookSink{
+		URL:     url,
+		Headers: headers,
+		HTTPClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
+	}
+}
+
+func (s *WebhookSink) SendTelemetry(payload TelemetryPayload) error {
+	return s.post(newEnvelope(TelemetryKindUsage, payload))
+}
+
+func (s *WebhookSink) SendExposure(event ExposureEvent) error {
+	return s.post(newEnvelope(TelemetryKindExposure, event))
+}
+
+func (s *WebhookSink) SendTrackingEvent(payload TrackingPayload) error {
+	return s.post(newEnvelope(TelemetryKindTracking, payload))
+}
+
+func (s *WebhookSink) SendMismatch(mismatch Mismatch) error {
+	return s.post(newEnvelope(TelemetryKindMismatch, mismatch))
+}
+
+func (s *WebhookSink) post(envelope TelemetryEnvelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", s.URL, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package toggle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTelemetryPayload() TelemetryPayload {
+	payload := TelemetryPayload{
+		Context: EvaluationContext{
+			TargetingKey: "user-123",
+			Application:  "test-app",
+			Environment:  "test-env",
+		},
+	}
+	payload.Data.Toggle = Evaluation{Key: "test-flag", Value: true, Type: "boolean"}
+	return payload
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+
+	assert.NoError(t, sink.SendTelemetry(testTelemetryPayload()))
+	assert.NoError(t, sink.SendExposure(ExposureEvent{FlagKey: "test-flag", Variant: "on"}))
+	assert.NoError(t, sink.SendTrackingEvent(TrackingPayload{}))
+
+	var kinds []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var envelope TelemetryEnvelope
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &envelope))
+		assert.False(t, envelope.Timestamp.IsZero())
+		kinds = append(kinds, envelope.Kind)
+	}
+	assert.Equal(t, []string{TelemetryKindUsage, TelemetryKindExposure, TelemetryKindTracking}, kinds)
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.ndjson")
+	sink, err := NewFileSink(path)
+	assert.NoError(t, err)
+	assert.NoError(t, sink.SendTelemetry(testTelemetryPayload()))
+	assert.NoError(t, sink.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"kind":"usage"`)
+	assert.Contains(t, string(data), `"key":"test-flag"`)
+
+	_, err = NewFileSink(filepath.Join(t.TempDir(), "missing", "telemetry.ndjson"))
+	assert.Error(t, err)
+}
+
+func TestMemorySink(t *testing.T) {
+	sink := NewMemorySink()
+	assert.NoError(t, sink.SendTelemetry(testTelemetryPayload()))
+	assert.NoError(t, sink.SendExposure(ExposureEvent{FlagKey: "test-flag"}))
+	assert.NoError(t, sink.SendTrackingEvent(TrackingPayload{}))
+
+	assert.Len(t, sink.Telemetry(), 1)
+	assert.Len(t, sink.Exposures(), 1)
+	assert.Len(t, sink.TrackingEvents(), 1)
+
+	sink.Reset()
+	assert.Empty(t, sink.Telemetry())
+	assert.Empty(t, sink.Exposures())
+	assert.Empty(t, sink.TrackingEvents())
+}
+
+func TestWebhookSink(t *testing.T) {
+	var received TelemetryEnvelope
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, map[string]string{"Authorization": "secret"})
+	assert.NoError(t, sink.SendTelemetry(testTelemetryPayload()))
+	assert.Equal(t, TelemetryKindUsage, received.Kind)
+
+	status = http.StatusInternalServerError
+	assert.EqualError(t, sink.SendExposure(ExposureEvent{}), "webhook returned status 500")
+}
+
+type failingSink struct{}
+
+func (failingSink) SendTelemetry(payload TelemetryPayload) error {
+	return errors.New("sink down")
+}
+
+func TestProvider_TelemetrySinks(t *testing.T) {
+	var horizonCalls int
+	memory := NewMemorySink()
+	disableUsage := false
+	p := &Provider{
+		client: &MockClient{
+			EvaluateFunc: func(ctx EvaluationContext) (*Response, error) {
+				return &Response{Toggles: map[string]Evaluation{
+					"test-flag": {Type: "boolean", Value: true},
+				}}, nil
+			},
+			SendTelemetryFunc: func(payload TelemetryPayload) error {
+				horizonCalls++
+				return nil
+			},
+		},
+		config: Config{
+			Application:    "test-app",
+			Environment:    "test-env",
+			EnableUsage:    &disableUsage,
+			TelemetrySinks: []TelemetrySink{memory},
+		},
+	}
+	p.hooks = []openfeature.Hook{NewProviderHook(p)}
+
+	assert.NoError(t, openfeature.SetNamedProviderAndWait("sink-test", p))
+	client := openfeature.NewClient("sink-test")
+	evalCtx := openfeature.NewEvaluationContext("user-123", nil)
+
+	_, err := client.BooleanValue(context.Background(), "test-flag", false, evalCtx)
+	assert.NoError(t, err)
+	client.Track(context.Background(), "checkout", evalCtx, openfeature.NewTrackingEventDetails(1))
+
+	assert.Equal(t, 0, horizonCalls)
+	assert.Len(t, memory.Telemetry(), 1)
+	assert.Equal(t, "test-flag", memory.Telemetry()[0].Data.Toggle.Key)
+	assert.Len(t, memory.TrackingEvents(), 1)
+
+	p.config.TelemetrySinks = []TelemetrySink{failingSink{}, memory}
+	err = p.sendTelemetry(testTelemetryPayload())
+	assert.EqualError(t, err, "sink down")
+	assert.Len(t, memory.Telemetry(), 2)
+}
@@ -0,0 +1,449 @@
+package toggle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// ReasonStale is the reason of StaticProvider evaluations served from toggles
+// fetched for a different context while the new one is reconciled.
+const ReasonStale openfeature.Reason = "STALE"
+
+// StaticConfig configures a StaticProvider.
+type StaticConfig struct {
+	Config
+	// RefreshInterval re-fetches the toggles for the current context periodically.
+	// Zero disables background refreshing.
+	RefreshInterval time.Duration
+}
+
+// StaticProvider is a provider for applications where a single user's context is
+// fixed, such as CLI tools and desktop or edge agents. It fetches every toggle once
+// for the current context and serves evaluations from memory, re-fetching on
+// SetContext, on RefreshInterval, or in the background when an evaluation arrives
+// with a different context.
+//
+// While a new context is reconciled the provider emits ProviderStale, followed by
+// ProviderConfigChange once the toggles are fetched or ProviderError if the fetch
+// fails. Events are delivered in order and never dropped.
+type StaticProvider struct {
+	provider     *Provider
+	config       StaticConfig
+	anonymousKey string
+	hooks        []openfeature.Hook
+	events       chan openfeature.Event
+
+	mu       sync.RWMutex
+	evalCtx  openfeature.FlattenedContext
+	response *Response
+	stale    bool
+	// target is the context most recently asked for and generation counts those
+	// requests, so a slow fetch cannot overwrite the result of a newer one.
+	target     openfeature.FlattenedContext
+	generation uint64
+
+	// fetchMu serializes fetches so concurrent context changes reconcile one at a time.
+	fetchMu sync.Mutex
+	done    chan struct{}
+	once    sync.Once
+
+	// queue holds the events not yet delivered to events; notify wakes deliverEvents.
+	queueMu sync.Mutex
+	queue   []openfeature.Event
+	notify  chan struct{}
+}
+
+func NewStaticProvider(config StaticConfig) (*StaticProvider, error) {
+	provider, err := NewProvider(config.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &StaticProvider{
+		provider:     provider,
+		config:       config,
+		anonymousKey: generateTargetingKey(config.Application, config.Environment),
+		events:       make(chan openfeature.Event, 10),
+		done:         make(chan struct{}),
+		notify:       make(chan struct{}, 1),
+	}
+	s.hooks = []openfeature.Hook{&staticHook{ProviderHook: NewProviderHook(provider), static: s}}
+	go s.deliverEvents()
+
+	return s, nil
+}
+
+func (s *StaticProvider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{
+		Name: "hyphen-static-provider",
+	}
+}
+
+func (s *StaticProvider) Hooks() []openfeature.Hook {
+	return s.hooks
+}
+
+// Init fetches the toggles for the initial evaluation context and starts background refreshing.
+func (s *StaticProvider) Init(evalCtx openfeature.EvaluationContext) error {
+	flat := s.withTargetingKey(flattenEvaluationContext(evalCtx))
+	if _, err := s.fetch(flat, s.request(flat)); err != nil {
+		return err
+	}
+	if s.config.RefreshInterval > 0 {
+		go s.refreshLoop()
+	}
+	return nil
+}
+
+func (s *StaticProvider) Shutdown() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+func (s *StaticProvider) EventChannel() <-chan openfeature.Event {
+	return s.events
+}
+
+// SetContext replaces the static evaluation context and fetches its toggles before
+// returning. Applications should call it alongside openfeature.SetEvaluationContext;
+// otherwise the change is reconciled in the background after the next evaluation,
+// which is served the previous toggles with a STALE reason meanwhile.
+func (s *StaticProvider) SetContext(evalCtx openfeature.EvaluationContext) error {
+	return s.reconcile(s.withTargetingKey(flattenEvaluationContext(evalCtx)))
+}
+
+func (s *StaticProvider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	value, detail := staticResolve(s, flag, defaultValue, evalCtx, coerceBool)
+	return openfeature.BoolResolutionDetail{
+		Value:                    value,
+		ProviderResolutionDetail: detail,
+	}
+}
+
+func (s *StaticProvider) StringEvaluation(ctx context.Context, flag string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	value, detail := staticResolve(s, flag, defaultValue, evalCtx, coerceString)
+	return openfeature.StringResolutionDetail{
+		Value:                    value,
+		ProviderResolutionDetail: detail,
+	}
+}
+
+func (s *StaticProvider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	value, detail := staticResolve(s, flag, defaultValue, evalCtx, coerceFloat)
+	return openfeature.FloatResolutionDetail{
+		Value:                    value,
+		ProviderResolutionDetail: detail,
+	}
+}
+
+func (s *StaticProvider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	value, detail := staticResolve(s, flag, defaultValue, evalCtx, coerceInt(s.config.IntRounding))
+	return openfeature.IntResolutionDetail{
+		Value:                    value,
+		ProviderResolutionDetail: detail,
+	}
+}
+
+func (s *StaticProvider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	value, detail := staticResolve(s, flag, defaultValue, evalCtx, coerceObject)
+	return openfeature.InterfaceResolutionDetail{
+		Value:                    value,
+		ProviderResolutionDetail: detail,
+	}
+}
+
+func staticResolve[T any](s *StaticProvider, flag string, defaultValue T, evalCtx openfeature.FlattenedContext, coerce coercer[T]) (T, openfeature.ProviderResolutionDetail) {
+	eval, stale, err := s.snapshot(evalCtx)
+	if errors.Is(err, ErrInvalidContext) {
+		return defaultValue, contextErrorDetail(err)
+	}
+	if err != nil {
+		return defaultValue, errorDetail(openfeature.NewGeneralResolutionError(err.Error()))
+	}
+	if eval == nil {
+		return defaultValue, errorDetail(openfeature.NewProviderNotReadyResolutionError("toggles have not been fetched"))
+	}
+
+	value, detail := resolveToggle(eval, flag, defaultValue, coerce)
+	if stale && detail.ResolutionDetail().ErrorCode == "" {
+		detail.Reason = ReasonStale
+	}
+	return value, detail
+}
+
+// snapshot returns the in-memory response without fetching. When evalCtx differs
+// from the context the toggles were fetched for, it reports them as stale and
+// reconciles evalCtx in the background.
+func (s *StaticProvider) snapshot(evalCtx openfeature.FlattenedContext) (*Response, bool, error) {
+	evalCtx = s.withTargetingKey(evalCtx)
+
+	s.mu.RLock()
+	current, response, target := s.evalCtx, s.response, s.target
+	s.mu.RUnlock()
+	if response != nil && reflect.DeepEqual(current, evalCtx) {
+		return response, false, nil
+	}
+
+	// Reject contexts that cannot be sent before scheduling a fetch for them.
+	if _, err := s.provider.buildContext(evalCtx); err != nil {
+		return nil, false, err
+	}
+	if !reflect.DeepEqual(target, evalCtx) {
+		go func() {
+			if err := s.reconcile(evalCtx); err != nil {
+				logError(context.Background(), "Error reconciling evaluation context:", err)
+			}
+		}()
+	}
+	return response, true, nil
+}
+
+// reconcile fetches the toggles for evalCtx and makes it the current context,
+// emitting ProviderStale before and ProviderConfigChange or ProviderError after.
+// A reconcile that stores nothing, because another one stored the same context or
+// superseded it, ends with ProviderReady, so every ProviderStale is followed up.
+// Reconciling the current context emits nothing.
+func (s *StaticProvider) reconcile(evalCtx openfeature.FlattenedContext) error {
+	generation := s.request(evalCtx)
+
+	s.mu.RLock()
+	previous := s.response
+	current := previous != nil && reflect.DeepEqual(s.evalCtx, evalCtx)
+	s.mu.RUnlock()
+	if current {
+		return nil
+	}
+
+	s.emit(openfeature.ProviderStale, openfeature.ProviderEventDetails{Message: "reconciling new evaluation context"})
+	applied, err := s.fetch(evalCtx, generation)
+	if err != nil {
+		s.mu.Lock()
+		if s.generation == generation {
+			// Let the next evaluation with this context try again.
+			s.target = s.evalCtx
+		}
+		s.mu.Unlock()
+		s.emit(openfeature.ProviderError, openfeature.ProviderEventDetails{
+			Message:   err.Error(),
+			ErrorCode: openfeature.GeneralCode,
+		})
+		return err
+	}
+	if !applied {
+		s.emit(openfeature.ProviderReady, openfeature.ProviderEventDetails{Message: "evaluation context reconciled"})
+		return nil
+	}
+	s.emit(openfeature.ProviderConfigChange, openfeature.ProviderEventDetails{
+		Message:     "toggles fetched for new evaluation context",
+		FlagChanges: changedFlags(previous, s.current()),
+	})
+	return nil
+}
+
+// request records evalCtx as the context most recently asked for and returns
+// the generation of the request.
+func (s *StaticProvider) request(evalCtx openfeature.FlattenedContext) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+	s.target = evalCtx
+	return s.generation
+}
+
+// fetch evaluates every toggle for evalCtx and stores the result as the current
+// snapshot. It reports false when nothing was stored, either because evalCtx is
+// already current or because a newer request superseded this one; the newer
+// request then emits its own events.
+func (s *StaticProvider) fetch(evalCtx openfeature.FlattenedContext, generation uint64) (bool, error) {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+
+	// Another caller may have reconciled the same context while we waited.
+	s.mu.RLock()
+	done := s.response != nil && reflect.DeepEqual(s.evalCtx, evalCtx)
+	superseded := s.generation != generation
+	s.mu.RUnlock()
+	if done || superseded {
+		return false, nil
+	}
+
+	response, err := s.evaluate(evalCtx)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.generation != generation {
+		return false, nil
+	}
+	s.evalCtx = evalCtx
+	s.response = response
+	s.stale = false
+	return true, nil
+}
+
+func (s *StaticProvider) evaluate(evalCtx openfeature.FlattenedContext) (*Response, error) {
+	hyphenCtx, err := s.provider.buildContext(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return s.provider.client.Evaluate(hyphenCtx)
+}
+
+func (s *StaticProvider) current() *Response {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.response
+}
+
+func (s *StaticProvider) refreshLoop() {
+	ticker := time.NewTicker(s.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.refresh()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// refresh re-fetches the toggles for the current context. On failure the previous
+// toggles keep being served and the provider is marked stale.
+func (s *StaticProvider) refresh() {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+
+	s.mu.RLock()
+	evalCtx, previous, stale := s.evalCtx, s.response, s.stale
+	s.mu.RUnlock()
+
+	response, err := s.evaluate(evalCtx)
+	if err != nil {
+		s.mu.Lock()
+		s.stale = true
+		s.mu.Unlock()
+		s.emit(openfeature.ProviderStale, openfeature.ProviderEventDetails{Message: err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	s.response = response
+	s.stale = false
+	s.mu.Unlock()
+
+	if changes := changedFlags(previous, response); len(changes) > 0 {
+		s.emit(openfeature.ProviderConfigChange, openfeature.ProviderEventDetails{
+			Message:     "toggles refreshed",
+			FlagChanges: changes,
+		})
+	} else if stale {
+		s.emit(openfeature.ProviderReady, openfeature.ProviderEventDetails{Message: "toggles refreshed"})
+	}
+}
+
+// withTargetingKey gives contexts without a targeting key the provider's stable
+// anonymous key, so the static context does not change between evaluations.
+func (s *StaticProvider) withTargetingKey(evalCtx openfeature.FlattenedContext) openfeature.FlattenedContext {
+	if key, ok := evalCtx[openfeature.TargetingKey].(string); ok && key != "" {
+		return evalCtx
+	}
+	withKey := make(openfeature.FlattenedContext, len(evalCtx)+1)
+	for k, v := range evalCtx {
+		withKey[k] = v
+	}
+	withKey[openfeature.TargetingKey] = s.anonymousKey
+	return withKey
+}
+
+// emit queues an event for deliverEvents. It never blocks, so evaluations and
+// fetches are not held up when nobody is listening.
+func (s *StaticProvider) emit(eventType openfeature.EventType, details openfeature.ProviderEventDetails) {
+	s.queueMu.Lock()
+	s.queue = append(s.queue, openfeature.Event{
+		ProviderName:         s.Metadata().Name,
+		EventType:            eventType,
+		ProviderEventDetails: details,
+	})
+	s.queueMu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// deliverEvents sends queued events to the event channel in order until Shutdown.
+func (s *StaticProvider) deliverEvents() {
+	for {
+		select {
+		case <-s.notify:
+		case <-s.done:
+			return
+		}
+
+		for {
+			s.queueMu.Lock()
+			if len(s.queue) == 0 {
+				s.queueMu.Unlock()
+				break
+			}
+			event := s.queue[0]
+			s.queue = s.queue[1:]
+			s.queueMu.Unlock()
+
+			select {
+			case s.events <- event:
+			case <-s.done:
+				return
+			}
+		}
+	}
+}
+
+// changedFlags lists the flags whose evaluation differs between two responses.
+func changedFlags(previous, next *Response) []string {
+	var changes []string
+	var prevToggles, nextToggles map[string]Evaluation
+	if previous != nil {
+		prevToggles = previous.Toggles
+	}
+	if next != nil {
+		nextToggles = next.Toggles
+	}
+	for key, toggle := range nextToggles {
+		if old, ok := prevToggles[key]; !ok || !reflect.DeepEqual(old, toggle) {
+			changes = append(changes, key)
+		}
+	}
+	for key := range prevToggles {
+		if _, ok := nextToggles[key]; !ok {
+			changes = append(changes, key)
+		}
+	}
+	return changes
+}
+
+// staticHook keeps the usage telemetry of ProviderHook but only fills in the
+// provider's stable anonymous targeting key, leaving the context otherwise untouched.
+type staticHook struct {
+	*ProviderHook
+	static *StaticProvider
+}
+
+func (h *staticHook) Before(ctx context.Context, hookContext openfeature.HookContext, hookHints openfeature.HookHints) (*openfeature.EvaluationContext, error) {
+	if hookContext.EvaluationContext().TargetingKey() != "" {
+		return nil, nil
+	}
+	newCtx := openfeature.NewEvaluationContext(h.static.anonymousKey, hookContext.EvaluationContext().Attributes())
+	return &newCtx, nil
+}
@@ -0,0 +1,326 @@
+package toggle
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStaticProvider(t *testing.T, evaluate func(ctx EvaluationContext) (*Response, error)) *StaticProvider {
+	s, err := NewStaticProvider(StaticConfig{
+		Config: Config{
+			PublicKey:   "public_" + base64.StdEncoding.EncodeToString([]byte("test-org:proj:random")),
+			Application: "test-app",
+			Environment: "test-env",
+		},
+	})
+	assert.NoError(t, err)
+	s.provider.client = &MockClient{EvaluateFunc: evaluate}
+	return s
+}
+
+// nextEvents waits for the next n events the provider delivers.
+func nextEvents(t *testing.T, s *StaticProvider, n int) []openfeature.EventType {
+	t.Helper()
+	var types []openfeature.EventType
+	for len(types) < n {
+		select {
+		case e := <-s.events:
+			types = append(types, e.EventType)
+		case <-time.After(time.Second):
+			t.Errorf("got events %v, want %d", types, n)
+			return types
+		}
+	}
+	return types
+}
+
+func drainEvents(s *StaticProvider) []openfeature.EventType {
+	var types []openfeature.EventType
+	for {
+		select {
+		case e := <-s.events:
+			types = append(types, e.EventType)
+		default:
+			return types
+		}
+	}
+}
+
+func TestStaticProvider_ServesFromMemory(t *testing.T) {
+	var calls int32
+	s := newTestStaticProvider(t, func(ctx EvaluationContext) (*Response, error) {
+		atomic.AddInt32(&calls, 1)
+		return &Response{Toggles: map[string]Evaluation{
+			"flag": {Type: "string", Value: ctx.TargetingKey},
+		}}, nil
+	})
+
+	assert.NoError(t, s.Init(openfeature.NewEvaluationContext("user-1", nil)))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	for i := 0; i < 3; i++ {
+		res := s.StringEvaluation(context.Background(), "flag", "default", evalCtx)
+		assert.Equal(t, "user-1", res.Value)
+		assert.NoError(t, res.Error())
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Empty(t, drainEvents(s))
+
+	res := s.BooleanEvaluation(context.Background(), "flag", true, evalCtx)
+	assert.True(t, res.Value)
+	assert.Equal(t, openfeature.ErrorReason, res.Reason)
+}
+
+func TestStaticProvider_ReconcilesOnContextChange(t *testing.T) {
+	var calls int32
+	s := newTestStaticProvider(t, func(ctx EvaluationContext) (*Response, error) {
+		atomic.AddInt32(&calls, 1)
+		return &Response{Toggles: map[string]Evaluation{
+			"flag": {Type: "string", Value: ctx.TargetingKey},
+		}}, nil
+	})
+	defer s.Shutdown()
+	assert.NoError(t, s.Init(openfeature.NewEvaluationContext("user-1", nil)))
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-2"}
+	res := s.StringEvaluation(context.Background(), "flag", "default", evalCtx)
+	assert.Equal(t, "user-1", res.Value)
+	assert.Equal(t, ReasonStale, res.Reason)
+	assert.NoError(t, res.Error())
+	assert.Equal(t, []openfeature.EventType{
+		openfeature.ProviderStale,
+		openfeature.ProviderConfigChange,
+	}, nextEvents(t, s, 2))
+
+	res = s.StringEvaluation(context.Background(), "flag", "default", evalCtx)
+	assert.Equal(t, "user-2", res.Value)
+	assert.NotEqual(t, ReasonStale, res.Reason)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	assert.NoError(t, s.SetContext(openfeature.NewEvaluationContext("user-3", nil)))
+	res = s.StringEvaluation(context.Background(), "flag", "default", openfeature.FlattenedContext{"targetingKey": "user-3"})
+	assert.Equal(t, "user-3", res.Value)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Equal(t, []openfeature.EventType{
+		openfeature.ProviderStale,
+		openfeature.ProviderConfigChange,
+	}, nextEvents(t, s, 2))
+}
+
+func TestStaticProvider_ReconcilesOncePerContext(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	s := newTestStaticProvider(t, func(ctx EvaluationContext) (*Response, error) {
+		if atomic.AddInt32(&calls, 1) > 1 {
+			<-release
+		}
+		return &Response{Toggles: map[string]Evaluation{
+			"flag": {Type: "string", Value: ctx.TargetingKey},
+		}}, nil
+	})
+	defer s.Shutdown()
+	assert.NoError(t, s.Init(openfeature.NewEvaluationContext("user-1", nil)))
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-2"}
+	for i := 0; i < 5; i++ {
+		res := s.StringEvaluation(context.Background(), "flag", "default", evalCtx)
+		assert.Equal(t, "user-1", res.Value)
+		assert.Equal(t, ReasonStale, res.Reason)
+	}
+	close(release)
+
+	assert.Equal(t, []openfeature.EventType{
+		openfeature.ProviderStale,
+		openfeature.ProviderConfigChange,
+	}, nextEvents(t, s, 2))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestStaticProvider_ReconcileFailure(t *testing.T) {
+	var fail int32
+	s := newTestStaticProvider(t, func(ctx EvaluationContext) (*Response, error) {
+		if atomic.LoadInt32(&fail) == 1 {
+			return nil, errors.New("unreachable")
+		}
+		return &Response{Toggles: map[string]Evaluation{
+			"flag": {Type: "string", Value: ctx.TargetingKey},
+		}}, nil
+	})
+	defer s.Shutdown()
+	assert.NoError(t, s.Init(openfeature.NewEvaluationContext("user-1", nil)))
+
+	atomic.StoreInt32(&fail, 1)
+	assert.Error(t, s.SetContext(openfeature.NewEvaluationContext("user-2", nil)))
+	assert.Equal(t, []openfeature.EventType{
+		openfeature.ProviderStale,
+		openfeature.ProviderError,
+	}, nextEvents(t, s, 2))
+
+	res := s.StringEvaluation(context.Background(), "flag", "default", openfeature.FlattenedContext{"targetingKey": "user-1"})
+	assert.Equal(t, "user-1", res.Value)
+	assert.NoError(t, res.Error())
+}
+
+func TestStaticProvider_NotReady(t *testing.T) {
+	release := make(chan struct{})
+	s := newTestStaticProvider(t, func(ctx EvaluationContext) (*Response, error) {
+		<-release
+		return &Response{Toggles: map[string]Evaluation{}}, nil
+	})
+	defer s.Shutdown()
+	defer close(release)
+
+	res := s.BooleanEvaluation(context.Background(), "flag", true, openfeature.FlattenedContext{"targetingKey": "user-1"})
+	assert.True(t, res.Value)
+	assert.Equal(t, openfeature.ProviderNotReadyCode, res.ResolutionDetail().ErrorCode)
+}
+
+func TestStaticProvider_EventsAreNotDropped(t *testing.T) {
+	s := newTestStaticProvider(t, func(ctx EvaluationContext) (*Response, error) {
+		return &Response{Toggles: map[string]Evaluation{
+			"flag": {Type: "string", Value: ctx.TargetingKey},
+		}}, nil
+	})
+	defer s.Shutdown()
+	assert.NoError(t, s.Init(openfeature.NewEvaluationContext("user-0", nil)))
+
+	// More reconciliations than the event channel buffers, with nobody listening.
+	for i := 1; i <= 20; i++ {
+		assert.NoError(t, s.SetContext(openfeature.NewEvaluationContext(fmt.Sprintf("user-%d", i), nil)))
+	}
+
+	events := nextEvents(t, s, 40)
+	for i := 0; i < len(events); i += 2 {
+		assert.Equal(t, openfeature.ProviderStale, events[i])
+		assert.Equal(t, openfeature.ProviderConfigChange, events[i+1])
+	}
+}
+
+func TestStaticProvider_StableAnonymousKey(t *testing.T) {
+	var keys []string
+	s := newTestStaticProvider(t, func(ctx EvaluationContext) (*Response, error) {
+		keys = append(keys, ctx.TargetingKey)
+		return &Response{Toggles: map[string]Evaluation{}}, nil
+	})
+
+	assert.NoError(t, s.Init(openfeature.NewTargetlessEvaluationContext(nil)))
+	s.BooleanEvaluation(context.Background(), "flag", false, openfeature.FlattenedContext{})
+	s.BooleanEvaluation(context.Background(), "flag", false, openfeature.FlattenedContext{})
+
+	assert.Len(t, keys, 1)
+	assert.Equal(t, s.anonymousKey, keys[0])
+}
+
+func TestStaticProvider_Refresh(t *testing.T) {
+	value := "v1"
+	var fail bool
+	s := newTestStaticProvider(t, func(ctx EvaluationContext) (*Response, error) {
+		if fail {
+			return nil, errors.New("unreachable")
+		}
+		return &Response{Toggles: map[string]Evaluation{
+			"flag": {Type: "string", Value: value},
+		}}, nil
+	})
+	assert.NoError(t, s.Init(openfeature.NewEvaluationContext("user-1", nil)))
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+
+	value = "v2"
+	s.refresh()
+	assert.Equal(t, []openfeature.EventType{openfeature.ProviderConfigChange}, nextEvents(t, s, 1))
+	assert.Equal(t, "v2", s.StringEvaluation(context.Background(), "flag", "default", evalCtx).Value)
+
+	fail = true
+	s.refresh()
+	assert.Equal(t, []openfeature.EventType{openfeature.ProviderStale}, nextEvents(t, s, 1))
+	assert.Equal(t, "v2", s.StringEvaluation(context.Background(), "flag", "default", evalCtx).Value)
+
+	fail = false
+	s.refresh()
+	assert.Equal(t, []openfeature.EventType{openfeature.ProviderReady}, nextEvents(t, s, 1))
+}
+
+func TestChangedFlags(t *testing.T) {
+	previous := &Response{Toggles: map[string]Evaluation{
+		"same":    {Type: "boolean", Value: true},
+		"changed": {Type: "boolean", Value: true},
+		"removed": {Type: "boolean", Value: true},
+	}}
+	next := &Response{Toggles: map[string]Evaluation{
+		"same":    {Type: "boolean", Value: true},
+		"changed": {Type: "boolean", Value: false},
+		"added":   {Type: "boolean", Value: true},
+	}}
+
+	assert.ElementsMatch(t, []string{"changed", "removed", "added"}, changedFlags(previous, next))
+	assert.Empty(t, changedFlags(next, next))
+}
+
+func TestStaticProvider_SetCurrentContext(t *testing.T) {
+	var calls int32
+	s := newTestStaticProvider(t, func(ctx EvaluationContext) (*Response, error) {
+		atomic.AddInt32(&calls, 1)
+		return &Response{Toggles: map[string]Evaluation{
+			"flag": {Type: "string", Value: ctx.TargetingKey},
+		}}, nil
+	})
+	defer s.Shutdown()
+	evalCtx := openfeature.NewEvaluationContext("user-1", nil)
+	assert.NoError(t, s.Init(evalCtx))
+
+	// Setting the current context again neither fetches nor marks the provider stale.
+	assert.NoError(t, s.SetContext(evalCtx))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	assert.NoError(t, s.SetContext(openfeature.NewEvaluationContext("user-2", nil)))
+	assert.Equal(t, []openfeature.EventType{
+		openfeature.ProviderStale,
+		openfeature.ProviderConfigChange,
+	}, nextEvents(t, s, 2))
+	assert.Empty(t, drainEvents(s))
+}
+
+func TestStaticProvider_SupersededReconcileEndsReady(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := newTestStaticProvider(t, func(ctx EvaluationContext) (*Response, error) {
+		if ctx.TargetingKey == "user-2" {
+			close(started)
+			<-release
+		}
+		return &Response{Toggles: map[string]Evaluation{
+			"flag": {Type: "string", Value: ctx.TargetingKey},
+		}}, nil
+	})
+	defer s.Shutdown()
+	assert.NoError(t, s.Init(openfeature.NewEvaluationContext("user-1", nil)))
+
+	done := make(chan error)
+	go func() { done <- s.SetContext(openfeature.NewEvaluationContext("user-2", nil)) }()
+	<-started
+	// user-3 is requested while user-2 is being fetched, superseding it.
+	go func() { done <- s.SetContext(openfeature.NewEvaluationContext("user-3", nil)) }()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	assert.NoError(t, <-done)
+	assert.NoError(t, <-done)
+
+	events := nextEvents(t, s, 4)
+	assert.ElementsMatch(t, []openfeature.EventType{
+		openfeature.ProviderStale,
+		openfeature.ProviderStale,
+		openfeature.ProviderReady,
+		openfeature.ProviderConfigChange,
+	}, events)
+	res := s.StringEvaluation(context.Background(), "flag", "default", openfeature.FlattenedContext{"targetingKey": "user-3"})
+	assert.Equal(t, "user-3", res.Value)
+}
@@ -3,16 +3,53 @@ package toggle
 import "time"
 
 type Config struct {
-	PublicKey   string
-	Application string
-	Environment string
-	HorizonUrls []string
-	EnableUsage *bool
-	Cache       *CacheConfig
+	PublicKey string
+	// StrictPublicKey makes NewProvider reject public keys that ParsePublicKey cannot
+	// parse, instead of falling back to the shared Horizon host.
+	StrictPublicKey bool
+	Application     string
+	Environment     string
+	HorizonUrls     []string
+	EnableUsage     *bool
+	Cache           *CacheConfig
+	// IntRounding controls how IntEvaluation treats non-integral numbers.
+	// The default, RoundingReject, resolves them with a type mismatch error.
+	IntRounding RoundingPolicy
+	// Exposures enables deduplicated experiment exposure events.
+	Exposures *ExposureConfig
+	// TelemetrySinks receive a copy of every telemetry event in addition to Horizon.
+	TelemetrySinks []TelemetrySink
+	// TelemetryFile appends every telemetry event to this file as newline-delimited
+	// JSON. The provider opens the file and keeps it open until Close, or until
+	// UpdateConfig changes the path.
+	TelemetryFile string
+	// TelemetryWebhook posts every telemetry event to this URL with the
+	// TelemetryWebhookHeaders, see WebhookSink.
+	TelemetryWebhook        string
+	TelemetryWebhookHeaders map[string]string
+	// AnonymousKey derives targeting keys for evaluations without a targeting key
+	// or user id. Defaults to RandomAnonymousKey.
+	AnonymousKey AnonymousKeyStrategy
+	// ContextMapper converts OpenFeature contexts into Hyphen evaluation contexts.
+	// Defaults to DefaultContextMapper.
+	ContextMapper ContextMapper
+	// ContextSchema validates evaluation contexts before they are sent to Horizon.
+	ContextSchema *ContextSchema
+	// Overrides force flag values locally. Overrides set with Provider.SetOverrides
+	// take precedence over these.
+	Overrides []Override
+	// AllowRequestOverrides honours overrides attached to the request context, such
+	// as the X-Hyphen-Override header read by ContextMiddleware. It is rejected unless
+	// Environment is listed in RequestOverrideEnvironments.
+	AllowRequestOverrides bool
+	// RequestOverrideEnvironments lists the non-production environments, by name or
+	// project environment ID, in which AllowRequestOverrides may be enabled.
+	RequestOverrideEnvironments []string
 }
 
 type CacheConfig struct {
-	TTL    time.Duration
+	TTL time.Duration
+	// KeyGen derives the cache key of an evaluation context. Defaults to DefaultCacheKey.
 	KeyGen func(ctx EvaluationContext) string
 }
 
@@ -33,15 +70,21 @@ type User struct {
 }
 
 type Evaluation struct {
-	Key    string      `json:"key"`
-	Value  interface{} `json:"value"`
-	Type   string      `json:"type"`
-	Reason string      `json:"reason,omitempty"`
-	Error  string      `json:"error,omitempty"`
+	Key     string      `json:"key"`
+	Value   interface{} `json:"value"`
+	Type    string      `json:"type"`
+	Reason  string      `json:"reason,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Variant string      `json:"variant,omitempty"`
+	Version string      `json:"version,omitempty"`
+	RuleID  string      `json:"ruleId,omitempty"`
+	Cached  bool        `json:"cached,omitempty"`
 }
 
 type Response struct {
 	Toggles map[string]Evaluation `json:"toggles"`
+	// Cached is set by the client when the response was served from its local cache.
+	Cached bool `json:"-"`
 }
 
 type TelemetryPayload struct {
@@ -50,3 +93,17 @@ type TelemetryPayload struct {
 		Toggle Evaluation `json:"toggle"`
 	} `json:"data"`
 }
+
+// TrackingPayload carries a custom business event recorded through the OpenFeature Tracking API.
+type TrackingPayload struct {
+	Context EvaluationContext `json:"context"`
+	Data    struct {
+		Event TrackingEvent `json:"event"`
+	} `json:"data"`
+}
+
+type TrackingEvent struct {
+	Name       string                 `json:"name"`
+	Value      float64                `json:"value"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
@@ -0,0 +1,166 @@
+// Package togglegrpc provides gRPC interceptors that propagate the Hyphen evaluation
+// context across service boundaries, so a user gets consistent flag values along a
+// chain of microservice calls.
+package togglegrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// DefaultTargetingKeyKey is the metadata key carrying the targeting key.
+	DefaultTargetingKeyKey = "x-hyphen-targeting-key"
+	// DefaultUserIDKey is the metadata key carrying the user id.
+	DefaultUserIDKey = "x-hyphen-user-id"
+)
+
+// Config controls which metadata keys carry the evaluation context.
+type Config struct {
+	// TargetingKeyKey is the metadata key for the targeting key. Defaults to DefaultTargetingKeyKey.
+	TargetingKeyKey string
+	// UserIDKey is the metadata key for the user id, stored as user.id. Defaults to DefaultUserIDKey.
+	UserIDKey string
+	// Attributes maps metadata keys to top-level evaluation context attributes,
+	// for example {"x-tenant": "tenant", "x-region": "region"}.
+	Attributes map[string]string
+}
+
+func (c Config) targetingKeyKey() string {
+	if c.TargetingKeyKey != "" {
+		return c.TargetingKeyKey
+	}
+	return DefaultTargetingKeyKey
+}
+
+func (c Config) userIDKey() string {
+	if c.UserIDKey != "" {
+		return c.UserIDKey
+	}
+	return DefaultUserIDKey
+}
+
+// UnaryServerInterceptor stores the evaluation context carried in incoming metadata
+// as the OpenFeature transaction context of each unary call.
+func UnaryServerInterceptor(config Config) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withIncomingContext(ctx, config), req)
+	}
+}
+
+// StreamServerInterceptor stores the evaluation context carried in incoming metadata
+// as the OpenFeature transaction context of each stream.
+func StreamServerInterceptor(config Config) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withIncomingContext(ss.Context(), config)})
+	}
+}
+
+// UnaryClientInterceptor propagates the targeting key and configured attributes of the
+// OpenFeature transaction context to downstream services as outgoing metadata.
+func UnaryClientInterceptor(config Config) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withOutgoingContext(ctx, config), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor propagates the targeting key and configured attributes of the
+// OpenFeature transaction context to downstream services as outgoing metadata.
+func StreamClientInterceptor(config Config) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withOutgoingContext(ctx, config), desc, cc, method, opts...)
+	}
+}
+
+// EvaluationContextFromMetadata builds an evaluation context in the shape the
+// toggle provider expects: the user id is nested under "user" and mapped metadata
+// becomes top-level attributes.
+func EvaluationContextFromMetadata(md metadata.MD, config Config) openfeature.EvaluationContext {
+	attributes := make(map[string]interface{})
+
+	for key, attribute := range config.Attributes {
+		if value := first(md, key); value != "" {
+			attributes[attribute] = value
+		}
+	}
+
+	userID := first(md, config.userIDKey())
+	if userID != "" {
+		attributes["user"] = map[string]interface{}{"id": userID}
+	}
+
+	targetingKey := first(md, config.targetingKeyKey())
+	if targetingKey == "" {
+		targetingKey = userID
+	}
+	if targetingKey == "" {
+		return openfeature.NewTargetlessEvaluationContext(attributes)
+	}
+	return openfeature.NewEvaluationContext(targetingKey, attributes)
+}
+
+// MetadataFromEvaluationContext encodes the targeting key, user id and configured
+// attributes of evalCtx as metadata.
+func MetadataFromEvaluationContext(evalCtx openfeature.EvaluationContext, config Config) metadata.MD {
+	md := metadata.MD{}
+
+	if key := evalCtx.TargetingKey(); key != "" {
+		md.Set(config.targetingKeyKey(), key)
+	}
+	if user, ok := evalCtx.Attribute("user").(map[string]interface{}); ok {
+		if id, ok := user["id"].(string); ok && id != "" {
+			md.Set(config.userIDKey(), id)
+		}
+	}
+
+	for key, attribute := range config.Attributes {
+		switch value := evalCtx.Attribute(attribute).(type) {
+		case nil, map[string]interface{}, []interface{}:
+			// only scalar attributes are propagated
+		default:
+			md.Set(key, fmt.Sprint(value))
+		}
+	}
+
+	return md
+}
+
+func withIncomingContext(ctx context.Context, config Config) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return openfeature.MergeTransactionContext(ctx, EvaluationContextFromMetadata(md, config))
+}
+
+func withOutgoingContext(ctx context.Context, config Config) context.Context {
+	md := MetadataFromEvaluationContext(openfeature.TransactionContext(ctx), config)
+	if len(md) == 0 {
+		return ctx
+	}
+	if existing, ok := metadata.FromOutgoingContext(ctx); ok {
+		md = metadata.Join(existing, md)
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// contextStream overrides the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,135 @@
+package togglegrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// capturingHealthServer records the transaction context seen by each call.
+type capturingHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	contexts chan openfeature.EvaluationContext
+}
+
+func (s *capturingHealthServer) Check(ctx context.Context, _ *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	s.contexts <- openfeature.TransactionContext(ctx)
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func (s *capturingHealthServer) Watch(_ *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	s.contexts <- openfeature.TransactionContext(stream.Context())
+	return stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING})
+}
+
+func newTestConn(t *testing.T, config Config) (grpc_health_v1.HealthClient, chan openfeature.EvaluationContext) {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(config)),
+		grpc.StreamInterceptor(StreamServerInterceptor(config)),
+	)
+	health := &capturingHealthServer{contexts: make(chan openfeature.EvaluationContext, 1)}
+	grpc_health_v1.RegisterHealthServer(server, health)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(config)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(config)),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return grpc_health_v1.NewHealthClient(conn), health.contexts
+}
+
+func TestInterceptors_Propagation(t *testing.T) {
+	config := Config{Attributes: map[string]string{"x-tenant": "tenant", "x-region": "region"}}
+	client, contexts := newTestConn(t, config)
+
+	ctx := openfeature.WithTransactionContext(context.Background(), openfeature.NewEvaluationContext("user-123", map[string]interface{}{
+		"tenant": "acme",
+		"region": "eu-west-1",
+		"user":   map[string]interface{}{"id": "user-123", "email": "user@example.com"},
+		"plan":   "enterprise",
+	}))
+
+	t.Run("unary", func(t *testing.T) {
+		_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		assert.NoError(t, err)
+
+		got := <-contexts
+		assert.Equal(t, "user-123", got.TargetingKey())
+		assert.Equal(t, "acme", got.Attribute("tenant"))
+		assert.Equal(t, "eu-west-1", got.Attribute("region"))
+		assert.Equal(t, map[string]interface{}{"id": "user-123"}, got.Attribute("user"))
+		assert.Nil(t, got.Attribute("plan"))
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+		assert.NoError(t, err)
+		_, err = stream.Recv()
+		assert.NoError(t, err)
+
+		got := <-contexts
+		assert.Equal(t, "user-123", got.TargetingKey())
+		assert.Equal(t, "acme", got.Attribute("tenant"))
+	})
+}
+
+func TestInterceptors_IncomingMetadata(t *testing.T) {
+	client, contexts := newTestConn(t, Config{
+		UserIDKey:  "x-user",
+		Attributes: map[string]string{"x-tenant": "tenant"},
+	})
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user", "user-456", "x-tenant", "globex")
+	_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+
+	got := <-contexts
+	assert.Equal(t, "user-456", got.TargetingKey())
+	assert.Equal(t, "globex", got.Attribute("tenant"))
+	assert.Equal(t, map[string]interface{}{"id": "user-456"}, got.Attribute("user"))
+}
+
+func TestInterceptors_NoContext(t *testing.T) {
+	client, contexts := newTestConn(t, Config{})
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+
+	got := <-contexts
+	assert.Empty(t, got.TargetingKey())
+	assert.Empty(t, got.Attributes())
+}
+
+func TestMetadataFromEvaluationContext(t *testing.T) {
+	config := Config{Attributes: map[string]string{"x-tenant": "tenant", "x-beta": "beta", "x-tags": "tags"}}
+	md := MetadataFromEvaluationContext(openfeature.NewEvaluationContext("key", map[string]interface{}{
+		"tenant": "acme",
+		"beta":   true,
+		"tags":   []interface{}{"a"},
+	}), config)
+
+	assert.Equal(t, []string{"key"}, md.Get(DefaultTargetingKeyKey))
+	assert.Equal(t, []string{"acme"}, md.Get("x-tenant"))
+	assert.Equal(t, []string{"true"}, md.Get("x-beta"))
+	assert.Empty(t, md.Get("x-tags"))
+	assert.Empty(t, md.Get(DefaultUserIDKey))
+}
@@ -0,0 +1,256 @@
+// Package toggletest provides test doubles for code built on the Hyphen provider:
+// an in-memory client and provider that record what they are asked, and a fake
+// Horizon server for tests that exercise the HTTP client.
+package toggletest
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/hyphen/openfeature-provider-go/pkg/toggle"
+)
+
+// EvaluateFunc answers an evaluation request in place of the flags set with SetFlag.
+type EvaluateFunc func(ctx toggle.EvaluationContext) (*toggle.Response, error)
+
+// flagSet holds the flags a Client or Server answers evaluations with.
+type flagSet struct {
+	flagsMu  sync.RWMutex
+	flags    map[string]toggle.Evaluation
+	evaluate EvaluateFunc
+}
+
+// SetFlag sets flag to value. The flag type is derived from the value the way
+// Horizon reports it: boolean, string, number or object.
+func (f *flagSet) SetFlag(flag string, value interface{}) {
+	f.SetEvaluation(evaluation(flag, value))
+}
+
+// SetFlags sets every flag in flags, as SetFlag does.
+func (f *flagSet) SetFlags(flags map[string]interface{}) {
+	for flag, value := range flags {
+		f.SetFlag(flag, value)
+	}
+}
+
+// SetEvaluation sets the full evaluation returned for e.Key, for tests that need a
+// variant, reason or Horizon error.
+func (f *flagSet) SetEvaluation(e toggle.Evaluation) {
+	f.flagsMu.Lock()
+	defer f.flagsMu.Unlock()
+	if f.flags == nil {
+		f.flags = make(map[string]toggle.Evaluation)
+	}
+	f.flags[e.Key] = e
+}
+
+// RemoveFlag removes flag, which then resolves as not found.
+func (f *flagSet) RemoveFlag(flag string) {
+	f.flagsMu.Lock()
+	defer f.flagsMu.Unlock()
+	delete(f.flags, flag)
+}
+
+// OnEvaluate answers every evaluation with fn, for responses that depend on the
+// context. A nil fn restores the flags set with SetFlag.
+func (f *flagSet) OnEvaluate(fn EvaluateFunc) {
+	f.flagsMu.Lock()
+	defer f.flagsMu.Unlock()
+	f.evaluate = fn
+}
+
+func (f *flagSet) respond(ctx toggle.EvaluationContext) (*toggle.Response, error) {
+	f.flagsMu.RLock()
+	evaluate := f.evaluate
+	toggles := make(map[string]toggle.Evaluation, len(f.flags))
+	for flag, e := range f.flags {
+		toggles[flag] = e
+	}
+	f.flagsMu.RUnlock()
+
+	if evaluate != nil {
+		return evaluate(ctx)
+	}
+	return &toggle.Response{Toggles: toggles}, nil
+}
+
+// evaluation converts value into the evaluation Horizon would send for it, with
+// numbers decoded as json.Number. Values that cannot be encoded are kept as objects.
+func evaluation(flag string, value interface{}) toggle.Evaluation {
+	decoded := value
+	if data, err := json.Marshal(value); err == nil {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&decoded); err != nil {
+			decoded = value
+		}
+	}
+
+	flagType := "object"
+	switch decoded.(type) {
+	case bool:
+		flagType = "boolean"
+	case string:
+		flagType = "string"
+	case json.Number:
+		flagType = "number"
+	}
+	return toggle.Evaluation{Key: flag, Type: flagType, Value: decoded}
+}
+
+// recorder captures the requests a Client or Server receives.
+type recorder struct {
+	mu        sync.Mutex
+	contexts  []toggle.EvaluationContext
+	telemetry []toggle.TelemetryPayload
+	tracking  []toggle.TrackingPayload
+	exposures []toggle.ExposureEvent
+}
+
+// Contexts returns the evaluation contexts received, in order.
+func (r *recorder) Contexts() []toggle.EvaluationContext {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]toggle.EvaluationContext(nil), r.contexts...)
+}
+
+// Telemetry returns the usage telemetry received, in order.
+func (r *recorder) Telemetry() []toggle.TelemetryPayload {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]toggle.TelemetryPayload(nil), r.telemetry...)
+}
+
+// TrackingEvents returns the tracking events received, in order.
+func (r *recorder) TrackingEvents() []toggle.TrackingPayload {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]toggle.TrackingPayload(nil), r.tracking...)
+}
+
+// Exposures returns the experiment exposures received, in order.
+func (r *recorder) Exposures() []toggle.ExposureEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]toggle.ExposureEvent(nil), r.exposures...)
+}
+
+// AssertTelemetry reports a test error unless usage telemetry was received for flag.
+func (r *recorder) AssertTelemetry(t testing.TB, flag string) bool {
+	t.Helper()
+	var seen []string
+	for _, payload := range r.Telemetry() {
+		if payload.Data.Toggle.Key == flag {
+			return true
+		}
+		seen = append(seen, payload.Data.Toggle.Key)
+	}
+	t.Errorf("toggletest: no telemetry for flag %q, got telemetry for %v", flag, seen)
+	return false
+}
+
+// AssertTracked reports a test error unless a tracking event named event was received.
+func (r *recorder) AssertTracked(t testing.TB, event string) bool {
+	t.Helper()
+	var seen []string
+	for _, payload := range r.TrackingEvents() {
+		if payload.Data.Event.Name == event {
+			return true
+		}
+		seen = append(seen, payload.Data.Event.Name)
+	}
+	t.Errorf("toggletest: event %q was not tracked, got %v", event, seen)
+	return false
+}
+
+func (r *recorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.contexts = nil
+	r.telemetry = nil
+	r.tracking = nil
+	r.exposures = nil
+}
+
+// Client is an in-memory toggle.ClientInterface. It answers evaluations with the
+// flags set on it and records every context, telemetry payload, tracking event
+// and exposure it receives. It is safe for concurrent use.
+type Client struct {
+	flagSet
+	recorder
+
+	errMu        sync.RWMutex
+	evaluateErr  error
+	telemetryErr error
+}
+
+// NewClient returns a client without flags, so every flag resolves as not found.
+func NewClient() *Client {
+	return &Client{}
+}
+
+// FailEvaluate makes evaluations fail with err, as when Horizon is unavailable.
+// A nil err restores them.
+func (c *Client) FailEvaluate(err error) {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+	c.evaluateErr = err
+}
+
+// FailTelemetry makes telemetry and tracking deliveries fail with err. They are
+// still recorded. A nil err restores them.
+func (c *Client) FailTelemetry(err error) {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+	c.telemetryErr = err
+}
+
+// Reset clears the recorded requests. Flags and failures are kept.
+func (c *Client) Reset() {
+	c.reset()
+}
+
+func (c *Client) Evaluate(ctx toggle.EvaluationContext) (*toggle.Response, error) {
+	c.mu.Lock()
+	c.contexts = append(c.contexts, ctx)
+	c.mu.Unlock()
+
+	c.errMu.RLock()
+	err := c.evaluateErr
+	c.errMu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	return c.respond(ctx)
+}
+
+func (c *Client) SendTelemetry(payload toggle.TelemetryPayload) error {
+	c.mu.Lock()
+	c.telemetry = append(c.telemetry, payload)
+	c.mu.Unlock()
+	return c.deliveryErr()
+}
+
+func (c *Client) SendTrackingEvent(payload toggle.TrackingPayload) error {
+	c.mu.Lock()
+	c.tracking = append(c.tracking, payload)
+	c.mu.Unlock()
+	return c.deliveryErr()
+}
+
+// SendExposure records an exposure. It makes the client an exposure sink, so
+// providers with exposures enabled report them here as they would to Horizon.
+func (c *Client) SendExposure(event toggle.ExposureEvent) error {
+	c.mu.Lock()
+	c.exposures = append(c.exposures, event)
+	c.mu.Unlock()
+	return c.deliveryErr()
+}
+
+func (c *Client) deliveryErr() error {
+	c.errMu.RLock()
+	defer c.errMu.RUnlock()
+	return c.telemetryErr
+}
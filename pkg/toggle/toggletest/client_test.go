@@ -0,0 +1,73 @@
+package toggletest
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/hyphen/openfeature-provider-go/pkg/toggle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluation(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     interface{}
+		wantType  string
+		wantValue interface{}
+	}{
+		{name: "boolean", value: true, wantType: "boolean", wantValue: true},
+		{name: "string", value: "dark", wantType: "string", wantValue: "dark"},
+		{name: "integer", value: 3, wantType: "number", wantValue: json.Number("3")},
+		{name: "float", value: 0.5, wantType: "number", wantValue: json.Number("0.5")},
+		{name: "object", value: map[string]int{"max": 3}, wantType: "object", wantValue: map[string]interface{}{"max": json.Number("3")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := evaluation("flag", tt.value)
+			assert.Equal(t, "flag", e.Key)
+			assert.Equal(t, tt.wantType, e.Type)
+			assert.Equal(t, tt.wantValue, e.Value)
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	client := NewClient()
+	client.SetFlags(map[string]interface{}{"theme": "dark", "limit": 3})
+	client.SetEvaluation(toggle.Evaluation{Key: "beta", Type: "boolean", Value: true, Variant: "on"})
+	client.RemoveFlag("limit")
+
+	ctx := toggle.EvaluationContext{TargetingKey: "user-1"}
+	resp, err := client.Evaluate(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Toggles, 2)
+	assert.Equal(t, "on", resp.Toggles["beta"].Variant)
+	assert.Equal(t, []toggle.EvaluationContext{ctx}, client.Contexts())
+
+	client.OnEvaluate(func(ctx toggle.EvaluationContext) (*toggle.Response, error) {
+		return &toggle.Response{Toggles: map[string]toggle.Evaluation{
+			"theme": evaluation("theme", ctx.TargetingKey),
+		}}, nil
+	})
+	resp, err = client.Evaluate(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", resp.Toggles["theme"].Value)
+	client.OnEvaluate(nil)
+
+	unavailable := errors.New("horizon unavailable")
+	client.FailEvaluate(unavailable)
+	_, err = client.Evaluate(ctx)
+	assert.ErrorIs(t, err, unavailable)
+
+	client.FailTelemetry(unavailable)
+	var payload toggle.TelemetryPayload
+	payload.Data.Toggle.Key = "theme"
+	assert.ErrorIs(t, client.SendTelemetry(payload), unavailable)
+	assert.Len(t, client.Telemetry(), 1)
+
+	client.Reset()
+	assert.Empty(t, client.Contexts())
+	assert.Empty(t, client.Telemetry())
+}
@@ -0,0 +1,176 @@
+package toggletest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hyphen/openfeature-provider-go/pkg/toggle"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Defaults filled in by NewProvider for the settings a test rarely cares about.
+// DefaultPublicKey parses, for organization and project "toggletest".
+const (
+	DefaultPublicKey   = "public_dG9nZ2xldGVzdDp0b2dnbGV0ZXN0OnNlY3JldA=="
+	DefaultApplication = "toggletest"
+	DefaultEnvironment = "test"
+)
+
+// Resolution is a flag evaluation recorded by Provider.
+type Resolution struct {
+	Flag      string
+	Type      openfeature.Type
+	Context   openfeature.FlattenedContext
+	Value     interface{}
+	Reason    openfeature.Reason
+	Variant   string
+	ErrorCode openfeature.ErrorCode
+}
+
+// Provider is a Hyphen provider backed by an in-memory Client. It behaves like
+// the real provider, hooks and telemetry included, and records every flag
+// evaluation for the assertion helpers. EvaluateAll is not recorded.
+type Provider struct {
+	*toggle.Provider
+	// Client answers the provider's evaluations. Set flags and failures on it.
+	Client *Client
+
+	mu          sync.Mutex
+	resolutions []Resolution
+}
+
+// NewProvider creates a provider backed by a new Client. PublicKey, Application
+// and Environment default to DefaultPublicKey, DefaultApplication and
+// DefaultEnvironment; the rest of config applies as it does to toggle.NewProvider.
+func NewProvider(config toggle.Config) (*Provider, error) {
+	if config.PublicKey == "" {
+		config.PublicKey = DefaultPublicKey
+	}
+	if config.Application == "" {
+		config.Application = DefaultApplication
+	}
+	if config.Environment == "" {
+		config.Environment = DefaultEnvironment
+	}
+
+	client := NewClient()
+	provider, err := toggle.NewProviderWithClient(config, client)
+	if err != nil {
+		return nil, err
+	}
+	return &Provider{Provider: provider, Client: client}, nil
+}
+
+func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	res := p.Provider.BooleanEvaluation(ctx, flag, defaultValue, evalCtx)
+	p.record(flag, openfeature.Boolean, evalCtx, res.Value, res.ProviderResolutionDetail)
+	return res
+}
+
+func (p *Provider) StringEvaluation(ctx context.Context, flag string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	res := p.Provider.StringEvaluation(ctx, flag, defaultValue, evalCtx)
+	p.record(flag, openfeature.String, evalCtx, res.Value, res.ProviderResolutionDetail)
+	return res
+}
+
+func (p *Provider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	res := p.Provider.FloatEvaluation(ctx, flag, defaultValue, evalCtx)
+	p.record(flag, openfeature.Float, evalCtx, res.Value, res.ProviderResolutionDetail)
+	return res
+}
+
+func (p *Provider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	res := p.Provider.IntEvaluation(ctx, flag, defaultValue, evalCtx)
+	p.record(flag, openfeature.Int, evalCtx, res.Value, res.ProviderResolutionDetail)
+	return res
+}
+
+func (p *Provider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	res := p.Provider.ObjectEvaluation(ctx, flag, defaultValue, evalCtx)
+	p.record(flag, openfeature.Object, evalCtx, res.Value, res.ProviderResolutionDetail)
+	return res
+}
+
+func (p *Provider) record(flag string, flagType openfeature.Type, evalCtx openfeature.FlattenedContext, value interface{}, detail openfeature.ProviderResolutionDetail) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resolutions = append(p.resolutions, Resolution{
+		Flag:      flag,
+		Type:      flagType,
+		Context:   evalCtx,
+		Value:     value,
+		Reason:    detail.Reason,
+		Variant:   detail.Variant,
+		ErrorCode: detail.ResolutionDetail().ErrorCode,
+	})
+}
+
+// Resolutions returns the recorded evaluations, in order.
+func (p *Provider) Resolutions() []Resolution {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Resolution(nil), p.resolutions...)
+}
+
+// ResolutionsOf returns the recorded evaluations of flag, in order.
+func (p *Provider) ResolutionsOf(flag string) []Resolution {
+	var out []Resolution
+	for _, r := range p.Resolutions() {
+		if r.Flag == flag {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Evaluated reports whether flag was evaluated with a context containing every
+// attribute. Nested attributes are addressed with dots, such as "user.email",
+// and the targeting key as "targetingKey". Nil attributes match any context.
+func (p *Provider) Evaluated(flag string, attributes map[string]interface{}) bool {
+	for _, r := range p.ResolutionsOf(flag) {
+		if (toggle.Override{Attributes: attributes}).Matches(r.Context) {
+			return true
+		}
+	}
+	return false
+}
+
+// AssertEvaluated reports a test error unless flag was evaluated with a context
+// containing every attribute, as Evaluated checks.
+func (p *Provider) AssertEvaluated(t testing.TB, flag string, attributes map[string]interface{}) bool {
+	t.Helper()
+	if p.Evaluated(flag, attributes) {
+		return true
+	}
+	resolutions := p.ResolutionsOf(flag)
+	if len(resolutions) == 0 {
+		t.Errorf("toggletest: flag %q was not evaluated", flag)
+		return false
+	}
+	contexts := make([]string, len(resolutions))
+	for i, r := range resolutions {
+		contexts[i] = fmt.Sprint(r.Context)
+	}
+	t.Errorf("toggletest: flag %q was not evaluated with %v, got contexts %v", flag, attributes, contexts)
+	return false
+}
+
+// AssertNotEvaluated reports a test error if flag was evaluated.
+func (p *Provider) AssertNotEvaluated(t testing.TB, flag string) bool {
+	t.Helper()
+	if n := len(p.ResolutionsOf(flag)); n > 0 {
+		t.Errorf("toggletest: flag %q was evaluated %d times", flag, n)
+		return false
+	}
+	return true
+}
+
+// Reset clears the recorded evaluations and the client's recorded requests.
+func (p *Provider) Reset() {
+	p.mu.Lock()
+	p.resolutions = nil
+	p.mu.Unlock()
+	p.Client.Reset()
+}
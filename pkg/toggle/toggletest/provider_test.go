@@ -0,0 +1,84 @@
+package toggletest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyphen/openfeature-provider-go/pkg/toggle"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+// failureRecorder captures test errors instead of failing the test.
+type failureRecorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *failureRecorder) Helper() {}
+
+func (r *failureRecorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestProvider(t *testing.T) {
+	provider, err := NewProvider(toggle.Config{})
+	assert.NoError(t, err)
+	provider.Client.SetFlags(map[string]interface{}{
+		"new-checkout": true,
+		"limit":        3,
+		"layout":       map[string]interface{}{"columns": 2},
+	})
+
+	assert.NoError(t, openfeature.SetNamedProviderAndWait("toggletest", provider))
+	client := openfeature.NewClient("toggletest")
+	evalCtx := openfeature.NewEvaluationContext("user-1", map[string]interface{}{
+		"user": map[string]interface{}{"email": "qa@example.com"},
+	})
+
+	enabled, err := client.BooleanValue(context.Background(), "new-checkout", false, evalCtx)
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+	limit, err := client.IntValue(context.Background(), "limit", 0, evalCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), limit)
+	_, err = client.ObjectValue(context.Background(), "layout", nil, evalCtx)
+	assert.NoError(t, err)
+	theme, err := client.StringValue(context.Background(), "theme", "light", evalCtx)
+	assert.Error(t, err)
+	assert.Equal(t, "light", theme)
+
+	provider.AssertEvaluated(t, "new-checkout", map[string]interface{}{
+		"targetingKey": "user-1",
+		"user.email":   "qa@example.com",
+		"application":  DefaultApplication,
+	})
+	provider.AssertEvaluated(t, "limit", nil)
+	provider.AssertNotEvaluated(t, "other-flag")
+	provider.Client.AssertTelemetry(t, "new-checkout")
+
+	resolutions := provider.ResolutionsOf("theme")
+	assert.Len(t, resolutions, 1)
+	assert.Equal(t, openfeature.String, resolutions[0].Type)
+	assert.Equal(t, openfeature.FlagNotFoundCode, resolutions[0].ErrorCode)
+
+	provider.Track(context.Background(), "checkout", evalCtx, openfeature.NewTrackingEventDetails(9.99))
+	provider.Client.AssertTracked(t, "checkout")
+
+	// Failed assertions are reported to the test.
+	recorder := &failureRecorder{TB: t}
+	assert.False(t, provider.AssertEvaluated(recorder, "new-checkout", map[string]interface{}{"targetingKey": "user-2"}))
+	assert.False(t, provider.AssertNotEvaluated(recorder, "limit"))
+	assert.False(t, provider.Client.AssertTelemetry(recorder, "other-flag"))
+	assert.Len(t, recorder.errors, 3)
+
+	provider.Reset()
+	assert.Empty(t, provider.Resolutions())
+	assert.Empty(t, provider.Client.Telemetry())
+}
+
+func TestNewProvider(t *testing.T) {
+	_, err := NewProvider(toggle.Config{Environment: "invalid environments"})
+	assert.ErrorIs(t, err, toggle.ErrInvalidEnvironmentFormat)
+}
@@ -0,0 +1,198 @@
+package toggletest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/hyphen/openfeature-provider-go/pkg/toggle"
+)
+
+// Paths served by Server, as requested by the Hyphen client.
+const (
+	EvaluatePath  = "/toggle/evaluate"
+	TelemetryPath = "/toggle/telemetry"
+)
+
+// failure scripts the responses of one path.
+type failure struct {
+	status int
+	// remaining counts the requests left to fail; negative fails until Recover.
+	remaining int
+}
+
+// Server is a fake Horizon server. It answers evaluations with the flags set on
+// it, records every context and telemetry payload posted to it, and can delay or
+// fail requests. Close it when the test ends.
+type Server struct {
+	*httptest.Server
+	flagSet
+	recorder
+
+	scriptMu sync.Mutex
+	latency  time.Duration
+	failures map[string]*failure
+	requests map[string]int
+}
+
+// NewServer starts a fake Horizon server without flags.
+func NewServer() *Server {
+	s := &Server{
+		failures: make(map[string]*failure),
+		requests: make(map[string]int),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(EvaluatePath, s.handle(s.serveEvaluate))
+	mux.HandleFunc(TelemetryPath, s.handle(s.serveTelemetry))
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Config returns a provider configuration that sends every request to the server.
+func (s *Server) Config() toggle.Config {
+	return toggle.Config{
+		PublicKey:   DefaultPublicKey,
+		Application: DefaultApplication,
+		Environment: DefaultEnvironment,
+		HorizonUrls: []string{s.URL},
+	}
+}
+
+// SetLatency delays every response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.scriptMu.Lock()
+	defer s.scriptMu.Unlock()
+	s.latency = d
+}
+
+// Fail answers the next times requests to path with status. A times of zero or
+// less fails every request to path until Recover.
+func (s *Server) Fail(path string, status int, times int) {
+	s.scriptMu.Lock()
+	defer s.scriptMu.Unlock()
+	if times <= 0 {
+		times = -1
+	}
+	s.failures[path] = &failure{status: status, remaining: times}
+}
+
+// Recover clears the failures scripted with Fail.
+func (s *Server) Recover() {
+	s.scriptMu.Lock()
+	defer s.scriptMu.Unlock()
+	s.failures = make(map[string]*failure)
+}
+
+// Requests returns the number of requests received for path, failed ones included.
+func (s *Server) Requests(path string) int {
+	s.scriptMu.Lock()
+	defer s.scriptMu.Unlock()
+	return s.requests[path]
+}
+
+// Reset clears the recorded requests and request counts. Flags, latency and
+// failures are kept.
+func (s *Server) Reset() {
+	s.reset()
+	s.scriptMu.Lock()
+	defer s.scriptMu.Unlock()
+	s.requests = make(map[string]int)
+}
+
+// handle applies the scripted latency and failures before serving a request.
+func (s *Server) handle(serve http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.scriptMu.Lock()
+		s.requests[r.URL.Path]++
+		latency := s.latency
+		status := 0
+		if f, ok := s.failures[r.URL.Path]; ok {
+			status = f.status
+			if f.remaining > 0 {
+				f.remaining--
+				if f.remaining == 0 {
+					delete(s.failures, r.URL.Path)
+				}
+			}
+		}
+		s.scriptMu.Unlock()
+
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if status != 0 {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		serve(w, r)
+	}
+}
+
+func (s *Server) serveEvaluate(w http.ResponseWriter, r *http.Request) {
+	var ctx toggle.EvaluationContext
+	if err := json.NewDecoder(r.Body).Decode(&ctx); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.contexts = append(s.contexts, ctx)
+	s.mu.Unlock()
+
+	resp, err := s.respond(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// telemetryBody is any payload posted to the telemetry endpoint. The field set
+// under data tells usage telemetry, tracking events and exposures apart.
+type telemetryBody struct {
+	Context toggle.EvaluationContext `json:"context"`
+	Data    struct {
+		Toggle   *toggle.Evaluation    `json:"toggle"`
+		Event    *toggle.TrackingEvent `json:"event"`
+		Exposure *toggle.ExposureEvent `json:"exposure"`
+	} `json:"data"`
+}
+
+func (s *Server) serveTelemetry(w http.ResponseWriter, r *http.Request) {
+	var body telemetryBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case body.Data.Toggle != nil:
+		payload := toggle.TelemetryPayload{Context: body.Context}
+		payload.Data.Toggle = *body.Data.Toggle
+		s.telemetry = append(s.telemetry, payload)
+	case body.Data.Event != nil:
+		payload := toggle.TrackingPayload{Context: body.Context}
+		payload.Data.Event = *body.Data.Event
+		s.tracking = append(s.tracking, payload)
+	case body.Data.Exposure != nil:
+		s.exposures = append(s.exposures, *body.Data.Exposure)
+	default:
+		http.Error(w, "unknown telemetry payload", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
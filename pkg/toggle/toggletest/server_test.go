@@ -0,0 +1,141 @@
+package toggletest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hyphen/openfeature-provider-go/pkg/toggle"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.SetFlags(map[string]interface{}{"new-checkout": true, "limit": 3})
+	server.SetEvaluation(toggle.Evaluation{Key: "theme", Type: "string", Value: "dark", Variant: "dark", Reason: "split"})
+
+	provider, err := toggle.NewProvider(server.Config())
+	assert.NoError(t, err)
+	assert.NoError(t, openfeature.SetNamedProviderAndWait("toggletest-server", provider))
+	client := openfeature.NewClient("toggletest-server")
+	evalCtx := openfeature.NewEvaluationContext("user-1", nil)
+
+	enabled, err := client.BooleanValue(context.Background(), "new-checkout", false, evalCtx)
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+	limit, err := client.IntValue(context.Background(), "limit", 0, evalCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), limit)
+	theme, err := client.StringValueDetails(context.Background(), "theme", "light", evalCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, "dark", theme.Value)
+	assert.Equal(t, "dark", theme.Variant)
+
+	contexts := server.Contexts()
+	assert.Len(t, contexts, 3)
+	assert.Equal(t, "user-1", contexts[0].TargetingKey)
+	assert.Equal(t, DefaultApplication, contexts[0].Application)
+	server.AssertTelemetry(t, "new-checkout")
+	assert.Equal(t, 3, server.Requests(EvaluatePath))
+	assert.Equal(t, 3, server.Requests(TelemetryPath))
+
+	provider.Track(context.Background(), "checkout", evalCtx, openfeature.NewTrackingEventDetails(9.99))
+	server.AssertTracked(t, "checkout")
+	assert.Equal(t, 9.99, server.TrackingEvents()[0].Data.Event.Value)
+
+	server.OnEvaluate(func(ctx toggle.EvaluationContext) (*toggle.Response, error) {
+		return &toggle.Response{Toggles: map[string]toggle.Evaluation{
+			"theme": {Key: "theme", Type: "string", Value: ctx.TargetingKey},
+		}}, nil
+	})
+	theme, err = client.StringValueDetails(context.Background(), "theme", "light", evalCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", theme.Value)
+
+	server.Reset()
+	assert.Empty(t, server.Contexts())
+	assert.Zero(t, server.Requests(EvaluatePath))
+}
+
+func TestServer_Failures(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.SetFlag("new-checkout", true)
+
+	second := NewServer()
+	defer second.Close()
+	second.SetFlag("new-checkout", false)
+
+	config := server.Config()
+	config.HorizonUrls = append(config.HorizonUrls, second.URL)
+	provider, err := toggle.NewProviderWithClient(config, nil)
+	assert.NoError(t, err)
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+
+	// A failing Horizon URL falls through to the next one.
+	server.Fail(EvaluatePath, http.StatusServiceUnavailable, 1)
+	res := provider.BooleanEvaluation(context.Background(), "new-checkout", true, evalCtx)
+	assert.False(t, res.Value)
+	assert.Equal(t, 1, server.Requests(EvaluatePath))
+	assert.Empty(t, server.Contexts())
+
+	res = provider.BooleanEvaluation(context.Background(), "new-checkout", false, evalCtx)
+	assert.True(t, res.Value)
+
+	server.Fail(EvaluatePath, http.StatusInternalServerError, 0)
+	second.Fail(EvaluatePath, http.StatusInternalServerError, 0)
+	for i := 0; i < 3; i++ {
+		res = provider.BooleanEvaluation(context.Background(), "new-checkout", false, evalCtx)
+		assert.Equal(t, openfeature.GeneralCode, res.ResolutionDetail().ErrorCode)
+	}
+	server.Recover()
+	assert.True(t, provider.BooleanEvaluation(context.Background(), "new-checkout", false, evalCtx).Value)
+
+	server.SetLatency(50 * time.Millisecond)
+	start := time.Now()
+	assert.True(t, provider.BooleanEvaluation(context.Background(), "new-checkout", false, evalCtx).Value)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	resp, err := http.Get(server.URL + EvaluatePath)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestServer_Config(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.SetFlag("new-checkout", true)
+
+	key, err := toggle.ParsePublicKey(server.Config().PublicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "toggletest", key.OrgID)
+
+	provider, err := toggle.NewProviderWithOptions(toggle.WithConfig(server.Config()), toggle.WithStrictPublicKey())
+	assert.NoError(t, err)
+	res := provider.BooleanEvaluation(context.Background(), "new-checkout", false, openfeature.FlattenedContext{"targetingKey": "user-1"})
+	assert.True(t, res.Value)
+	assert.Equal(t, 1, server.Requests(EvaluatePath))
+}
+
+func TestServer_Cache(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.SetFlag("new-checkout", true)
+
+	provider, err := toggle.NewProviderWithOptions(toggle.WithConfig(server.Config()), toggle.WithCache(time.Minute))
+	assert.NoError(t, err)
+
+	// Without a KeyGen, identical contexts share a cache entry.
+	for i := 0; i < 3; i++ {
+		res := provider.BooleanEvaluation(context.Background(), "new-checkout", false, openfeature.FlattenedContext{"targetingKey": "user-1"})
+		assert.True(t, res.Value)
+	}
+	assert.Equal(t, 1, server.Requests(EvaluatePath))
+
+	provider.BooleanEvaluation(context.Background(), "new-checkout", false, openfeature.FlattenedContext{"targetingKey": "user-2"})
+	assert.Equal(t, 2, server.Requests(EvaluatePath))
+}
@@ -0,0 +1,49 @@
+package toggle
+
+import (
+	"context"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Track records a custom business event, such as a conversion, through Horizon's
+// telemetry endpoints so it can be linked to the flag exposures of the same user.
+// Events are also delivered to every TelemetrySink that implements TrackingSink.
+// Tracking is independent of EnableUsage. Delivery failures are reported through
+// the logger stored under the "logger" context key.
+func (p *Provider) Track(ctx context.Context, trackingEventName string, evalCtx openfeature.EvaluationContext, details openfeature.TrackingEventDetails) {
+	flat := flattenEvaluationContext(evalCtx)
+	if _, ok := flat[openfeature.TargetingKey].(string); !ok {
+		config := p.settings()
+		attributes := evalCtx.Attributes()
+		attributes["application"] = config.Application
+		attributes["environment"] = config.Environment
+		if key, err := p.resolveTargetingKey(attributes); err == nil {
+			flat[openfeature.TargetingKey] = key
+		}
+	}
+
+	hyphenCtx, err := p.buildContext(flat)
+	if err != nil {
+		logError(ctx, "Error tracking event:", err)
+		return
+	}
+
+	payload := TrackingPayload{Context: hyphenCtx}
+	payload.Data.Event = TrackingEvent{
+		Name:       trackingEventName,
+		Value:      details.Value(),
+		Attributes: details.Attributes(),
+	}
+
+	if err := p.sendTrackingEvent(payload); err != nil {
+		logError(ctx, "Error tracking event:", err)
+	}
+}
+
+func (s *StaticProvider) Track(ctx context.Context, trackingEventName string, evalCtx openfeature.EvaluationContext, details openfeature.TrackingEventDetails) {
+	if evalCtx.TargetingKey() == "" {
+		evalCtx = openfeature.NewEvaluationContext(s.anonymousKey, evalCtx.Attributes())
+	}
+	s.provider.Track(ctx, trackingEventName, evalCtx, details)
+}
@@ -0,0 +1,111 @@
+package toggle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingLogger struct {
+	messages [][]interface{}
+}
+
+func (l *recordingLogger) Error(args ...interface{}) {
+	l.messages = append(l.messages, args)
+}
+
+func TestProvider_Track(t *testing.T) {
+	var payloads []TrackingPayload
+	p := &Provider{
+		client: &MockClient{
+			SendTrackingEventFunc: func(payload TrackingPayload) error {
+				payloads = append(payloads, payload)
+				return nil
+			},
+		},
+		config: Config{
+			Application: "test-app",
+			Environment: "test-env",
+		},
+	}
+
+	var _ openfeature.Tracker = p
+
+	evalCtx := openfeature.NewEvaluationContext("user-123", map[string]interface{}{"plan": "premium"})
+	details := openfeature.NewTrackingEventDetails(49.99).Add("currency", "USD")
+	p.Track(context.Background(), "checkout", evalCtx, details)
+
+	assert.Len(t, payloads, 1)
+	assert.Equal(t, "checkout", payloads[0].Data.Event.Name)
+	assert.Equal(t, 49.99, payloads[0].Data.Event.Value)
+	assert.Equal(t, map[string]interface{}{"currency": "USD"}, payloads[0].Data.Event.Attributes)
+	assert.Equal(t, "user-123", payloads[0].Context.TargetingKey)
+	assert.Equal(t, "test-app", payloads[0].Context.Application)
+	assert.Equal(t, "premium", payloads[0].Context.CustomAttributes["plan"])
+
+	// Without a targeting key the user id is used instead.
+	userCtx := openfeature.NewTargetlessEvaluationContext(map[string]interface{}{
+		"user": map[string]interface{}{"id": "user-456"},
+	})
+	p.Track(context.Background(), "signup", userCtx, openfeature.NewTrackingEventDetails(1))
+	assert.Len(t, payloads, 2)
+	assert.Equal(t, "user-456", payloads[1].Context.TargetingKey)
+}
+
+func TestProvider_TrackErrors(t *testing.T) {
+	p := &Provider{
+		client: &MockClient{
+			SendTrackingEventFunc: func(payload TrackingPayload) error {
+				return errors.New("unreachable")
+			},
+		},
+		config: Config{
+			Application:  "test-app",
+			Environment:  "test-env",
+			AnonymousKey: RejectAnonymous(),
+		},
+	}
+
+	logger := &recordingLogger{}
+	ctx := context.WithValue(context.Background(), "logger", logger)
+
+	p.Track(ctx, "checkout", openfeature.NewTargetlessEvaluationContext(nil), openfeature.NewTrackingEventDetails(1))
+	p.Track(ctx, "checkout", openfeature.NewEvaluationContext("user-123", nil), openfeature.NewTrackingEventDetails(1))
+
+	assert.Len(t, logger.messages, 2)
+	assert.Equal(t, ErrMissingTargetKey, logger.messages[0][1])
+	assert.EqualError(t, logger.messages[1][1].(error), "unreachable")
+}
+
+// telemetryOnlyClient implements ClientInterface without TrackingClient.
+type telemetryOnlyClient struct{}
+
+func (telemetryOnlyClient) Evaluate(ctx EvaluationContext) (*Response, error) {
+	return &Response{}, nil
+}
+
+func (telemetryOnlyClient) SendTelemetry(payload TelemetryPayload) error {
+	return nil
+}
+
+func TestProvider_TrackWithoutTrackingClient(t *testing.T) {
+	sink := NewMemorySink()
+	p := &Provider{
+		client: telemetryOnlyClient{},
+		config: Config{
+			Application:    "test-app",
+			Environment:    "test-env",
+			TelemetrySinks: []TelemetrySink{sink},
+		},
+	}
+
+	logger := &recordingLogger{}
+	ctx := context.WithValue(context.Background(), "logger", logger)
+	p.Track(ctx, "checkout", openfeature.NewEvaluationContext("user-123", nil), openfeature.NewTrackingEventDetails(1))
+
+	assert.Empty(t, logger.messages)
+	assert.Len(t, sink.TrackingEvents(), 1)
+}